@@ -448,6 +448,26 @@ func NewAdvancedMap[TKey comparable, TValue any]() *AdvancedMap[TKey, TValue] {
 	return mapUtils.NewAdvancedMap[TKey, TValue]()
 }
 
+func NewSafeValueMap[TKey comparable, TValue any]() *SafeValueMap[TKey, TValue] {
+	return mapUtils.NewSafeValueMap[TKey, TValue]()
+}
+
+func NewSafeEValueMap[TKey comparable, TValue any]() *SafeEValueMap[TKey, TValue] {
+	return mapUtils.NewSafeEValueMap[TKey, TValue]()
+}
+
+func NewCowMap[TKey comparable, TValue any]() *CowMap[TKey, TValue] {
+	return mapUtils.NewCowMap[TKey, TValue]()
+}
+
+func NewKeyedMutex[TKey comparable]() *KeyedMutex[TKey] {
+	return mapUtils.NewKeyedMutex[TKey]()
+}
+
+func NewKeyedRWMutex[TKey comparable]() *KeyedRWMutex[TKey] {
+	return mapUtils.NewKeyedRWMutex[TKey]()
+}
+
 // NewNumIdGenerator initializes an empty NumIdGenerator and returns it.
 // The current value will be set to the default value of the type (0).
 // The returned value is safe to use in concurrent environments.
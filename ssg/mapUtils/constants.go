@@ -22,3 +22,19 @@ const (
 	checkActionBreak
 	checkActionReturn
 )
+
+const (
+	// ExpiryDispatchGoroutine starts a new goroutine for every expiry callback.
+	// This is the default mode; the callbacks are unbounded and unordered.
+	ExpiryDispatchGoroutine ExpiryDispatchMode = iota
+
+	// ExpiryDispatchPool runs the expiry callbacks on a bounded pool of workers.
+	// All of the callbacks of the same key run on the same worker, so they are
+	// invoked in the order the values expired.
+	ExpiryDispatchPool
+
+	// ExpiryDispatchSync runs the expiry callbacks one by one in the goroutine
+	// that called DoCheck (the checker loop, for automatic checks), after the map
+	// lock is released.
+	ExpiryDispatchSync
+)
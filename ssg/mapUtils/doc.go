@@ -2,14 +2,22 @@
 //
 // # Copy safety
 //
-// The map types store pointers to TValue, but some APIs copy TValue by accepting
-// or returning it by value. If TValue must not be copied after first use, as with
-// sync.Mutex, sync.RWMutex, sync.Once, sync.WaitGroup, and the sync/atomic types,
-// avoid value-oriented APIs such as GetValue, GetRandomValue, ToArray,
-// ToNormalMap, AddList, value-form Set, SetDefault, and SetOnExpired.
+// SafeMap, SafeEMap and AdvancedMap store pointers to TValue, but some APIs copy
+// TValue by accepting or returning it by value. If TValue must not be copied
+// after first use, as with sync.Mutex, sync.RWMutex, sync.Once, sync.WaitGroup,
+// and the sync/atomic types, avoid value-oriented APIs such as GetValue,
+// GetRandomValue, ToArray, ToNormalMap, AddList, value-form Set, SetDefault, and
+// SetOnExpired.
 //
 // Use pointer-oriented APIs instead: Add, AddPointerList, Get, GetWithOptions,
 // GetRandom, ToPointerArray, ToList, pointer-form Set, and SetOnExpiredPtr. Go does
 // not prevent copying synchronization values at runtime; go vet only provides
 // best-effort static detection.
+//
+// # Value maps
+//
+// SafeValueMap and SafeEValueMap store TValue inline instead of behind a pointer,
+// which avoids one heap allocation per entry and reduces GC pressure for large
+// maps of small values. All of their APIs copy TValue, so they must not be used
+// with values that are unsafe to copy.
 package mapUtils
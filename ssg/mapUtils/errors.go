@@ -0,0 +1,8 @@
+package mapUtils
+
+import "errors"
+
+var (
+	ErrIndexAlreadyExists  = errors.New("index already exists")
+	ErrUniqueIndexConflict = errors.New("unique index conflict")
+)
@@ -7,5 +7,18 @@ func NewAdvancedMap[TKey comparable, TValue any]() *AdvancedMap[TKey, TValue] {
 		mut:           &sync.RWMutex{},
 		values:        make(map[TKey]*TValue),
 		sliceKeyIndex: make(map[TKey]int),
+		indexes:       make(map[string]*advancedIndex[TKey, TValue]),
+	}
+}
+
+func newAdvancedIndex[TKey comparable, TValue any](
+	keyFn func(*TValue) any,
+	unique bool,
+) *advancedIndex[TKey, TValue] {
+	return &advancedIndex[TKey, TValue]{
+		keyFn:       keyFn,
+		unique:      unique,
+		entries:     make(map[any]map[TKey]struct{}),
+		indexValues: make(map[TKey]any),
 	}
 }
@@ -0,0 +1,22 @@
+package mapUtils
+
+import "sync"
+
+func NewCowMap[TKey comparable, TValue any]() *CowMap[TKey, TValue] {
+	return NewCowMapFrom[TKey, TValue](nil)
+}
+
+// NewCowMapFrom returns a new CowMap initialized with a copy of the specified map.
+func NewCowMapFrom[TKey comparable, TValue any](m map[TKey]TValue) *CowMap[TKey, TValue] {
+	s := &CowMap[TKey, TValue]{
+		writeMut: &sync.Mutex{},
+	}
+
+	values := make(map[TKey]TValue, len(m))
+	for k, v := range m {
+		values[k] = v
+	}
+	s.snapshot.Store(&values)
+
+	return s
+}
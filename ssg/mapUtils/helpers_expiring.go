@@ -1,6 +1,8 @@
 package mapUtils
 
 import (
+	"hash/maphash"
+	"runtime"
 	"sync"
 	"time"
 )
@@ -20,3 +22,32 @@ func NewSafeEMap[TKey comparable, TValue any]() *SafeEMap[TKey, TValue] {
 		sliceKeyIndex: make(map[TKey]int),
 	}
 }
+
+func newExpiryDispatcher(opts *ExpiryDispatchOptions) *expiryDispatcher {
+	if opts == nil || opts.Mode == ExpiryDispatchGoroutine {
+		return nil
+	}
+
+	d := &expiryDispatcher{
+		mode: opts.Mode,
+		seed: maphash.MakeSeed(),
+	}
+
+	if opts.Mode != ExpiryDispatchPool {
+		return d
+	}
+
+	workersCount := opts.Workers
+	if workersCount < 1 {
+		workersCount = runtime.NumCPU()
+	}
+
+	d.workers = make([]*expiryWorker, workersCount)
+	for i := range d.workers {
+		d.workers[i] = &expiryWorker{
+			mut: &sync.Mutex{},
+		}
+	}
+
+	return d
+}
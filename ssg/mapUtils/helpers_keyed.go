@@ -0,0 +1,22 @@
+package mapUtils
+
+import "sync"
+
+func newKeyedLocker[TKey comparable]() *keyedLocker[TKey] {
+	return &keyedLocker[TKey]{
+		mut:     &sync.Mutex{},
+		entries: make(map[TKey]*keyedLockEntry),
+	}
+}
+
+func NewKeyedMutex[TKey comparable]() *KeyedMutex[TKey] {
+	return &KeyedMutex[TKey]{
+		locker: newKeyedLocker[TKey](),
+	}
+}
+
+func NewKeyedRWMutex[TKey comparable]() *KeyedRWMutex[TKey] {
+	return &KeyedRWMutex[TKey]{
+		locker: newKeyedLocker[TKey](),
+	}
+}
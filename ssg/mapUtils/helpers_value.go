@@ -0,0 +1,17 @@
+package mapUtils
+
+import "sync"
+
+func NewSafeValueMap[TKey comparable, TValue any]() *SafeValueMap[TKey, TValue] {
+	return &SafeValueMap[TKey, TValue]{
+		mut:    &sync.RWMutex{},
+		values: make(map[TKey]TValue),
+	}
+}
+
+func NewSafeEValueMap[TKey comparable, TValue any]() *SafeEValueMap[TKey, TValue] {
+	return &SafeEValueMap[TKey, TValue]{
+		mut:     &sync.RWMutex{},
+		indexes: make(map[TKey]int),
+	}
+}
@@ -1,6 +1,7 @@
 package mapUtils
 
 import (
+	"fmt"
 	"math/rand"
 
 	"github.com/ALiwoto/ssg/ssg/commonUtils"
@@ -29,20 +30,34 @@ func (s *AdvancedMap[TKey, TValue]) Exists(key TKey) bool {
 	return exists
 }
 
+// Add sets the key to the value, and updates the secondary indexes of the map.
+// If the value conflicts with another key in a unique index, nothing is changed;
+// use TryAdd to get the error.
 func (s *AdvancedMap[TKey, TValue]) Add(key TKey, value *TValue) {
+	_ = s.TryAdd(key, value)
+}
+
+// TryAdd sets the key to the value, and updates the secondary indexes of the map.
+// If the value conflicts with another key in a unique index, nothing is changed
+// and an error wrapping ErrUniqueIndexConflict is returned.
+func (s *AdvancedMap[TKey, TValue]) TryAdd(key TKey, value *TValue) error {
 	s.lock()
 	defer s.unlock()
 
-	s.setValue(key, value)
+	return s.setValue(key, value)
 }
 
 // setValue replaces the value for an existing key or registers a new key in
 // all of the map's internal indexes. The caller must hold the map's write lock.
-func (s *AdvancedMap[TKey, TValue]) setValue(key TKey, value *TValue) {
+func (s *AdvancedMap[TKey, TValue]) setValue(key TKey, value *TValue) error {
+	if err := s.updateIndexes(key, value); err != nil {
+		return err
+	}
+
 	_, exists := s.values[key]
 	s.values[key] = value
 	if exists {
-		return
+		return nil
 	}
 
 	s.keys = append(s.keys, key)
@@ -50,6 +65,7 @@ func (s *AdvancedMap[TKey, TValue]) setValue(key TKey, value *TValue) {
 	// store the index of the map key
 	index := len(s.keys) - 1
 	s.sliceKeyIndex[key] = index
+	return nil
 }
 func (s *AdvancedMap[TKey, TValue]) GetRandom() *TValue {
 	s.rLock()
@@ -178,6 +194,8 @@ func (s *AdvancedMap[TKey, TValue]) ToList() listUtils.GenericList[*TValue] {
 	return list
 }
 
+// AddList adds all of the elements to the map. Elements rejected by a unique
+// index are skipped.
 func (s *AdvancedMap[TKey, TValue]) AddList(keyGetter func(*TValue) TKey, elements ...TValue) {
 	if len(elements) == 0 || keyGetter == nil {
 		return
@@ -188,6 +206,8 @@ func (s *AdvancedMap[TKey, TValue]) AddList(keyGetter func(*TValue) TKey, elemen
 	}
 }
 
+// AddPointerList adds all of the elements to the map. Elements rejected by a
+// unique index are skipped.
 func (s *AdvancedMap[TKey, TValue]) AddPointerList(keyGetter func(*TValue) TKey, elements ...*TValue) {
 	if len(elements) == 0 || keyGetter == nil {
 		return
@@ -227,6 +247,9 @@ func (s *AdvancedMap[TKey, TValue]) delete(key TKey, useLock bool) {
 	}
 
 	delete(s.values, key)
+	for _, idx := range s.indexes {
+		idx.remove(key)
+	}
 }
 
 func (s *AdvancedMap[TKey, TValue]) Delete(key TKey) {
@@ -301,8 +324,8 @@ func (s *AdvancedMap[TKey, TValue]) GetWithOptions(
 				return false
 			}
 
-			s.setValue(key, value)
-			return true
+			// a value rejected by a unique index is treated as not created.
+			return s.setValue(key, value) == nil
 		}()
 		if !retry {
 			return nil
@@ -353,18 +376,26 @@ func (s *AdvancedMap[TKey, TValue]) SetDefault(value TValue) {
 // Set function sets the key of type TKey in this safe map to the value.
 // the value should be of type TValue or *TValue, otherwise this function won't
 // do anything at all.
+// Just like Add, values rejected by a unique index are not stored; use TrySet
+// to get the error.
 func (s *AdvancedMap[TKey, TValue]) Set(key TKey, value any) {
+	_ = s.TrySet(key, value)
+}
+
+// TrySet is like Set, but returns an error wrapping ErrUniqueIndexConflict if
+// the value is rejected by a unique index.
+func (s *AdvancedMap[TKey, TValue]) TrySet(key TKey, value any) error {
 	correctValue, ok := value.(*TValue)
 	if !ok {
 		anotherValue, ok := value.(TValue)
 		if !ok {
-			return
+			return nil
 		}
 
 		correctValue = &anotherValue
 	}
 
-	s.Add(key, correctValue)
+	return s.TryAdd(key, correctValue)
 }
 
 // Clear will clear the whole map.
@@ -375,6 +406,9 @@ func (s *AdvancedMap[TKey, TValue]) Clear() {
 	s.values = make(map[TKey]*TValue)
 	s.keys = nil
 	s.sliceKeyIndex = make(map[TKey]int)
+	for _, idx := range s.indexes {
+		idx.clear()
+	}
 }
 
 func (s *AdvancedMap[TKey, TValue]) Length() int {
@@ -421,3 +455,219 @@ func (s *AdvancedMap[TKey, TValue]) IsValid() bool {
 }
 
 //---------------------------------------------------------
+
+// AddIndex adds a secondary index with the specified name to the map.
+// keyFn is called with each value of the map (never with nil) and must return
+// a comparable value to index it by; values for which keyFn returns nil are
+// not indexed. If unique is true, Add and Set reject values whose index value
+// is already used by another key (TryAdd and TrySet report it as an error).
+// The index is built from the current entries of the map; if a unique index
+// can't be built because of conflicting entries, an error wrapping
+// ErrUniqueIndexConflict is returned and the index is not added.
+//
+// The indexes are updated inside Add, Set, Delete, DeleteIf and ForEach.
+// Modifying a value in place (for example through a pointer returned from Get)
+// does NOT update the indexes; set the value again to re-index it.
+// keyFn runs while the map is locked; re-using this map inside of it will
+// result in a deadlock.
+func (s *AdvancedMap[TKey, TValue]) AddIndex(
+	name string,
+	keyFn func(*TValue) any,
+	unique bool,
+) error {
+	s.lock()
+	defer s.unlock()
+
+	if _, exists := s.indexes[name]; exists {
+		return fmt.Errorf("%w: %q", ErrIndexAlreadyExists, name)
+	}
+
+	idx := newAdvancedIndex[TKey](keyFn, unique)
+	for key, value := range s.values {
+		indexValue := idx.valueOf(value)
+		if err := idx.check(name, key, indexValue); err != nil {
+			return err
+		}
+
+		idx.set(key, indexValue)
+	}
+
+	if s.indexes == nil {
+		s.indexes = make(map[string]*advancedIndex[TKey, TValue])
+	}
+	s.indexes[name] = idx
+	return nil
+}
+
+// RemoveIndex removes the secondary index with the specified name from the map.
+func (s *AdvancedMap[TKey, TValue]) RemoveIndex(name string) {
+	s.lock()
+	defer s.unlock()
+
+	delete(s.indexes, name)
+}
+
+// HasIndex reports whether the map has a secondary index with the specified name.
+func (s *AdvancedMap[TKey, TValue]) HasIndex(name string) bool {
+	s.rLock()
+	defer s.rUnlock()
+
+	_, exists := s.indexes[name]
+	return exists
+}
+
+// GetBy returns a value whose index value in the specified index equals
+// indexValue, or nil if there is none (or the index doesn't exist).
+// Index values are compared like map keys of type any, so their dynamic types
+// must be identical too: int(5) doesn't match int64(5).
+// For non-unique indexes, any one of the matching values may be returned;
+// use GetAllBy to get all of them.
+func (s *AdvancedMap[TKey, TValue]) GetBy(index string, indexValue any) *TValue {
+	s.rLock()
+	defer s.rUnlock()
+
+	idx := s.indexes[index]
+	if idx == nil || indexValue == nil {
+		return nil
+	}
+
+	for key := range idx.entries[indexValue] {
+		return s.values[key]
+	}
+
+	return nil
+}
+
+// GetAllBy returns all of the values whose index value in the specified index
+// equals indexValue (compared as in GetBy). The order of the returned values is
+// not specified.
+func (s *AdvancedMap[TKey, TValue]) GetAllBy(index string, indexValue any) []*TValue {
+	s.rLock()
+	defer s.rUnlock()
+
+	idx := s.indexes[index]
+	if idx == nil || indexValue == nil {
+		return nil
+	}
+
+	keys := idx.entries[indexValue]
+	if len(keys) == 0 {
+		return nil
+	}
+
+	result := make([]*TValue, 0, len(keys))
+	for key := range keys {
+		result = append(result, s.values[key])
+	}
+
+	return result
+}
+
+// GetKeysBy returns all of the map keys whose index value in the specified
+// index equals indexValue (compared as in GetBy).
+func (s *AdvancedMap[TKey, TValue]) GetKeysBy(index string, indexValue any) []TKey {
+	s.rLock()
+	defer s.rUnlock()
+
+	idx := s.indexes[index]
+	if idx == nil || indexValue == nil {
+		return nil
+	}
+
+	var result []TKey
+	for key := range idx.entries[indexValue] {
+		result = append(result, key)
+	}
+
+	return result
+}
+
+// updateIndexes re-indexes key with value in all of the secondary indexes.
+// It checks all of the unique indexes first, so either every index is updated
+// or none of them are. The caller must hold the map's write lock.
+func (s *AdvancedMap[TKey, TValue]) updateIndexes(key TKey, value *TValue) error {
+	if len(s.indexes) == 0 {
+		return nil
+	}
+
+	indexValues := make(map[string]any, len(s.indexes))
+	for name, idx := range s.indexes {
+		indexValue := idx.valueOf(value)
+		if err := idx.check(name, key, indexValue); err != nil {
+			return err
+		}
+
+		indexValues[name] = indexValue
+	}
+
+	for name, idx := range s.indexes {
+		idx.remove(key)
+		idx.set(key, indexValues[name])
+	}
+
+	return nil
+}
+
+//---------------------------------------------------------
+
+// valueOf returns the index value of value, or nil if value is nil.
+func (i *advancedIndex[TKey, TValue]) valueOf(value *TValue) any {
+	if value == nil {
+		return nil
+	}
+
+	return i.keyFn(value)
+}
+
+// check returns an error if setting key to indexValue would violate the
+// uniqueness of this index.
+func (i *advancedIndex[TKey, TValue]) check(name string, key TKey, indexValue any) error {
+	if !i.unique || indexValue == nil {
+		return nil
+	}
+
+	for otherKey := range i.entries[indexValue] {
+		if otherKey != key {
+			return fmt.Errorf(
+				"%w: index %q already has value %v for key %v",
+				ErrUniqueIndexConflict, name, indexValue, otherKey,
+			)
+		}
+	}
+
+	return nil
+}
+
+func (i *advancedIndex[TKey, TValue]) set(key TKey, indexValue any) {
+	if indexValue == nil {
+		return
+	}
+
+	keys := i.entries[indexValue]
+	if keys == nil {
+		keys = make(map[TKey]struct{})
+		i.entries[indexValue] = keys
+	}
+
+	keys[key] = struct{}{}
+	i.indexValues[key] = indexValue
+}
+
+func (i *advancedIndex[TKey, TValue]) remove(key TKey) {
+	indexValue, exists := i.indexValues[key]
+	if !exists {
+		return
+	}
+
+	delete(i.indexValues, key)
+	keys := i.entries[indexValue]
+	delete(keys, key)
+	if len(keys) == 0 {
+		delete(i.entries, indexValue)
+	}
+}
+
+func (i *advancedIndex[TKey, TValue]) clear() {
+	i.entries = make(map[any]map[TKey]struct{})
+	i.indexValues = make(map[TKey]any)
+}
@@ -0,0 +1,255 @@
+package mapUtils
+
+import "slices"
+
+func (s *CowMap[TKey, TValue]) lock() {
+	s.writeMut.Lock()
+}
+
+func (s *CowMap[TKey, TValue]) unlock() {
+	s.writeMut.Unlock()
+}
+
+// load returns the current snapshot. The returned map must never be modified.
+func (s *CowMap[TKey, TValue]) load() map[TKey]TValue {
+	return *s.snapshot.Load()
+}
+
+// clone returns a writable copy of the current snapshot, with room for
+// extra more entries. The caller must hold the write lock.
+func (s *CowMap[TKey, TValue]) clone(extra int) map[TKey]TValue {
+	current := s.load()
+	values := make(map[TKey]TValue, len(current)+extra)
+	for k, v := range current {
+		values[k] = v
+	}
+
+	return values
+}
+
+// store publishes values as the new snapshot. The caller must hold the write
+// lock and must not modify values afterwards.
+func (s *CowMap[TKey, TValue]) store(values map[TKey]TValue) {
+	s.snapshot.Store(&values)
+}
+
+func (s *CowMap[TKey, TValue]) Exists(key TKey) bool {
+	_, exists := s.load()[key]
+	return exists
+}
+
+// Get returns the value of the key and whether it exists in the map or not.
+// It never locks.
+func (s *CowMap[TKey, TValue]) Get(key TKey) (TValue, bool) {
+	value, exists := s.load()[key]
+	return value, exists
+}
+
+// GetValue returns the value of the key, or the default value of the map
+// if the key doesn't exist. It never locks.
+func (s *CowMap[TKey, TValue]) GetValue(key TKey) TValue {
+	value, exists := s.load()[key]
+	if !exists {
+		if defaultValue := s.defaultValue.Load(); defaultValue != nil {
+			return *defaultValue
+		}
+	}
+
+	return value
+}
+
+func (s *CowMap[TKey, TValue]) SetDefault(value TValue) {
+	s.defaultValue.Store(&value)
+}
+
+// ForEach calls fn for each entry of the current snapshot without locking.
+// Writes made while iterating (including writes from inside fn) are not
+// visible to this iteration. Remove operations returned by the callback are
+// treated as continue or break operations and do not modify the map; use
+// DeleteIf or Batch to remove entries.
+func (s *CowMap[TKey, TValue]) ForEach(fn func(TKey, TValue) ForEachOperation) {
+	if fn == nil {
+		return
+	}
+
+myFor:
+	for key, value := range s.load() {
+		switch fn(key, value) {
+		case ForEachOperationContinue, ForEachOperationRemove:
+			continue
+		case ForEachOperationBreak, ForEachOperationRemoveBreak:
+			break myFor
+		}
+	}
+}
+
+// Snapshot returns the current immutable snapshot of the map.
+// The returned map is shared with other readers and MUST NOT be modified;
+// use ToNormalMap to get a copy that can be modified.
+func (s *CowMap[TKey, TValue]) Snapshot() map[TKey]TValue {
+	return s.load()
+}
+
+func (s *CowMap[TKey, TValue]) ToNormalMap() map[TKey]TValue {
+	current := s.load()
+	m := make(map[TKey]TValue, len(current))
+	for k, v := range current {
+		m[k] = v
+	}
+
+	return m
+}
+
+func (s *CowMap[TKey, TValue]) ToArray() []TValue {
+	current := s.load()
+	array := make([]TValue, 0, len(current))
+	for _, v := range current {
+		array = append(array, v)
+	}
+
+	return array
+}
+
+func (s *CowMap[TKey, TValue]) Length() int {
+	return len(s.load())
+}
+
+func (s *CowMap[TKey, TValue]) IsEmpty() bool {
+	return s.Length() == 0
+}
+
+// Set sets the key to the value by cloning the current snapshot.
+func (s *CowMap[TKey, TValue]) Set(key TKey, value TValue) {
+	s.lock()
+	defer s.unlock()
+
+	values := s.clone(1)
+	values[key] = value
+	s.store(values)
+}
+
+// SetMany sets all of the specified entries with a single swap.
+func (s *CowMap[TKey, TValue]) SetMany(entries map[TKey]TValue) {
+	if len(entries) == 0 {
+		return
+	}
+
+	s.lock()
+	defer s.unlock()
+
+	values := s.clone(len(entries))
+	for k, v := range entries {
+		values[k] = v
+	}
+	s.store(values)
+}
+
+// Delete removes the key from the map. It doesn't swap the snapshot if the
+// key doesn't exist.
+func (s *CowMap[TKey, TValue]) Delete(key TKey) {
+	s.lock()
+	defer s.unlock()
+
+	if _, exists := s.load()[key]; !exists {
+		return
+	}
+
+	values := s.clone(0)
+	delete(values, key)
+	s.store(values)
+}
+
+// DeleteMany removes all of the specified keys with a single swap. Like Delete,
+// it doesn't swap the snapshot if none of the keys exist.
+func (s *CowMap[TKey, TValue]) DeleteMany(keys ...TKey) {
+	if len(keys) == 0 {
+		return
+	}
+
+	s.lock()
+	defer s.unlock()
+
+	current := s.load()
+	if !slices.ContainsFunc(keys, func(key TKey) bool {
+		_, exists := current[key]
+		return exists
+	}) {
+		return
+	}
+
+	values := s.clone(0)
+	for _, key := range keys {
+		delete(values, key)
+	}
+	s.store(values)
+}
+
+// DeleteIf deletes key when condFn returns true for its value.
+// condFn runs while the write lock is held; writing to this map inside condFn
+// will result in a deadlock, reading from it is fine.
+func (s *CowMap[TKey, TValue]) DeleteIf(key TKey, condFn func(TValue) bool) {
+	if condFn == nil {
+		return
+	}
+
+	s.lock()
+	defer s.unlock()
+
+	value, exists := s.load()[key]
+	if !exists || !condFn(value) {
+		return
+	}
+
+	values := s.clone(0)
+	delete(values, key)
+	s.store(values)
+}
+
+// Batch calls fn with a writable copy of the current snapshot, and publishes
+// the copy with a single swap after fn returns. If fn returns false, the
+// changes are discarded.
+// fn runs while the write lock is held; writing to this map inside fn will
+// result in a deadlock, reading from it returns the old snapshot.
+func (s *CowMap[TKey, TValue]) Batch(fn func(values map[TKey]TValue) bool) {
+	if fn == nil {
+		return
+	}
+
+	s.lock()
+	defer s.unlock()
+
+	values := s.clone(0)
+	if fn(values) {
+		s.store(values)
+	}
+}
+
+// Replace replaces the whole contents of the map with a copy of the specified
+// map, with a single swap.
+func (s *CowMap[TKey, TValue]) Replace(m map[TKey]TValue) {
+	values := make(map[TKey]TValue, len(m))
+	for k, v := range m {
+		values[k] = v
+	}
+
+	s.lock()
+	defer s.unlock()
+
+	s.store(values)
+}
+
+// Clear will clear the whole map.
+func (s *CowMap[TKey, TValue]) Clear() {
+	s.lock()
+	defer s.unlock()
+
+	s.store(make(map[TKey]TValue))
+}
+
+func (s *CowMap[TKey, TValue]) IsThreadSafe() bool {
+	return true
+}
+
+func (s *CowMap[TKey, TValue]) IsValid() bool {
+	return s != nil && s.writeMut != nil && s.snapshot.Load() != nil
+}
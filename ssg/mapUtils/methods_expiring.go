@@ -630,6 +630,42 @@ func (s *SafeEMap[TKey, TValue]) SetOnExpiredPtr(event func(key TKey, value *TVa
 	s.onExpiredPtr = event
 }
 
+// SetOnExpiredBatch sets the event function that will be called once per check
+// with all of the values expired during that check. It is called after the
+// per-entry callbacks in ExpiryDispatchSync mode; in the other modes it runs
+// concurrently with them.
+func (s *SafeEMap[TKey, TValue]) SetOnExpiredBatch(
+	event func(entries []ExpiredEntry[TKey, TValue]),
+) {
+	s.lock()
+	defer s.unlock()
+
+	s.onExpiredBatch = event
+}
+
+// SetOnCallbackPanic sets the function that is called when one of the expiry
+// callbacks panics. The panic is always recovered, so it never crashes the
+// process; if no function is set, it is silently dropped.
+// For panics in the batch callback, key is the zero value of TKey.
+func (s *SafeEMap[TKey, TValue]) SetOnCallbackPanic(fn func(key TKey, recovered any)) {
+	s.lock()
+	defer s.unlock()
+
+	s.onCallbackPanic = fn
+}
+
+// SetExpiryDispatch sets how the expiry callbacks are invoked. Passing nil
+// restores the default mode, ExpiryDispatchGoroutine.
+// Callbacks already queued in a previous ExpiryDispatchPool still run.
+func (s *SafeEMap[TKey, TValue]) SetExpiryDispatch(opts *ExpiryDispatchOptions) {
+	dispatcher := newExpiryDispatcher(opts)
+
+	s.lock()
+	defer s.unlock()
+
+	s.dispatcher = dispatcher
+}
+
 // SetPreExpiringConditionFn sets the condition checked under the map's write lock
 // before an expired value is removed or replaced. Returning false keeps the value;
 // GetWithOptions then treats it as found, refreshes its timestamp, and calls DoFn.
@@ -674,33 +710,60 @@ func (s *SafeEMap[TKey, TValue]) getRealValue(
 }
 
 // DoCheck iterates over the map and checks for expired variables and removes them.
-// if the `onExpired` member of the map is set, it will call them.
+// if any of the expiry callbacks of the map is set, it will call them after the
+// map's lock is released, according to the map's dispatch mode.
 func (s *SafeEMap[TKey, TValue]) DoCheck() {
+	callbacks, entries := s.removeExpired()
+	callbacks.dispatch(entries)
+}
+
+// removeExpired removes the expired values from the map, and returns them
+// alongside a snapshot of the expiry callbacks.
+func (s *SafeEMap[TKey, TValue]) removeExpired() (
+	*expiryCallbacks[TKey, TValue],
+	[]ExpiredEntry[TKey, TValue],
+) {
 	s.lock()
 	defer s.unlock()
 
+	callbacks := &expiryCallbacks[TKey, TValue]{
+		onExpired:       s.onExpired,
+		onExpiredPtr:    s.onExpiredPtr,
+		onExpiredBatch:  s.onExpiredBatch,
+		onCallbackPanic: s.onCallbackPanic,
+		dispatcher:      s.dispatcher,
+		defaultValue:    s.defaultValue,
+	}
+
 	if s.disabled || len(s.values) == 0 {
-		return
+		return callbacks, nil
 	}
 
+	var entries []ExpiredEntry[TKey, TValue]
 	for key, current := range s.values {
 		if current == nil || current.IsExpired(s.expiration) {
+			var value *TValue
+			if current != nil {
+				value = current.GetValue(false)
+			}
+
 			if current != nil &&
 				s.preExpiringConditionFn != nil &&
-				!s.preExpiringConditionFn(key, current.GetValue(false)) {
+				!s.preExpiringConditionFn(key, value) {
 				continue
 			}
 
 			s.delete(key, false)
-			if s.onExpired != nil {
-				go s.onExpired(key, s.getRealValue(current, false))
-			}
-
-			if s.onExpiredPtr != nil {
-				go s.onExpiredPtr(key, current.GetValue(false))
+			if callbacks.hasAny() {
+				entries = append(entries, ExpiredEntry[TKey, TValue]{
+					Key:   key,
+					Value: value,
+				})
 			}
 		}
 	}
+
+	return callbacks, entries
 }
 
 func (s *SafeEMap[TKey, TValue]) getCheckStatus() checkAction {
@@ -0,0 +1,148 @@
+package mapUtils
+
+import "hash/maphash"
+
+// submit queues task on this worker, and starts the worker's goroutine if
+// it's not already running.
+func (w *expiryWorker) submit(task func()) {
+	w.mut.Lock()
+	defer w.mut.Unlock()
+
+	w.queue = append(w.queue, task)
+	if w.running {
+		return
+	}
+
+	w.running = true
+	go w.run()
+}
+
+func (w *expiryWorker) run() {
+	for {
+		w.mut.Lock()
+		if len(w.queue) == 0 {
+			w.running = false
+			w.mut.Unlock()
+			return
+		}
+
+		task := w.queue[0]
+		w.queue[0] = nil
+		w.queue = w.queue[1:]
+		w.mut.Unlock()
+
+		task()
+	}
+}
+
+// workerFor returns the worker responsible for the specified key.
+func workerFor[TKey comparable](d *expiryDispatcher, key TKey) *expiryWorker {
+	return d.workers[maphash.Comparable(d.seed, key)%uint64(len(d.workers))]
+}
+
+// hasAny reports whether any expiry callback is set.
+func (c *expiryCallbacks[TKey, TValue]) hasAny() bool {
+	return c.onExpired != nil || c.onExpiredPtr != nil || c.onExpiredBatch != nil
+}
+
+// protect runs fn, and reports a panic in it to the panic callback instead of
+// crashing the process.
+func (c *expiryCallbacks[TKey, TValue]) protect(key TKey, fn func()) {
+	defer func() {
+		if r := recover(); r != nil && c.onCallbackPanic != nil {
+			c.onCallbackPanic(key, r)
+		}
+	}()
+
+	fn()
+}
+
+func (c *expiryCallbacks[TKey, TValue]) callOnExpired(entry ExpiredEntry[TKey, TValue]) {
+	c.protect(entry.Key, func() {
+		value := c.defaultValue
+		if entry.Value != nil {
+			value = *entry.Value
+		}
+
+		c.onExpired(entry.Key, value)
+	})
+}
+
+func (c *expiryCallbacks[TKey, TValue]) callOnExpiredPtr(entry ExpiredEntry[TKey, TValue]) {
+	c.protect(entry.Key, func() {
+		c.onExpiredPtr(entry.Key, entry.Value)
+	})
+}
+
+// callEntry invokes the per-entry callbacks of entry, in order.
+func (c *expiryCallbacks[TKey, TValue]) callEntry(entry ExpiredEntry[TKey, TValue]) {
+	if c.onExpired != nil {
+		c.callOnExpired(entry)
+	}
+
+	if c.onExpiredPtr != nil {
+		c.callOnExpiredPtr(entry)
+	}
+}
+
+// callBatch invokes the batch callback. A panic in it is reported with the
+// zero value of TKey.
+func (c *expiryCallbacks[TKey, TValue]) callBatch(entries []ExpiredEntry[TKey, TValue]) {
+	var zeroKey TKey
+	c.protect(zeroKey, func() {
+		c.onExpiredBatch(entries)
+	})
+}
+
+// dispatch invokes the expiry callbacks for entries according to the
+// dispatch mode. It must be called without holding the map's lock.
+func (c *expiryCallbacks[TKey, TValue]) dispatch(entries []ExpiredEntry[TKey, TValue]) {
+	if len(entries) == 0 || !c.hasAny() {
+		return
+	}
+
+	mode := ExpiryDispatchGoroutine
+	if c.dispatcher != nil {
+		mode = c.dispatcher.mode
+	}
+
+	switch mode {
+	case ExpiryDispatchSync:
+		for _, entry := range entries {
+			c.callEntry(entry)
+		}
+
+		if c.onExpiredBatch != nil {
+			c.callBatch(entries)
+		}
+	case ExpiryDispatchPool:
+		for _, entry := range entries {
+			if c.onExpired != nil || c.onExpiredPtr != nil {
+				workerFor(c.dispatcher, entry.Key).submit(func() {
+					c.callEntry(entry)
+				})
+			}
+		}
+
+		if c.onExpiredBatch != nil {
+			// batches always go to the first worker, so they are delivered in order.
+			c.dispatcher.workers[0].submit(func() {
+				c.callBatch(entries)
+			})
+		}
+	default:
+		for _, entry := range entries {
+			if c.onExpired != nil {
+				go c.callOnExpired(entry)
+			}
+
+			if c.onExpiredPtr != nil {
+				go c.callOnExpiredPtr(entry)
+			}
+		}
+
+		if c.onExpiredBatch != nil {
+			go c.callBatch(entries)
+		}
+	}
+}
@@ -0,0 +1,647 @@
+package mapUtils
+
+import (
+	"math/rand"
+	"sync/atomic"
+	"time"
+)
+
+func (s *SafeEValueMap[TKey, TValue]) lock() {
+	s.mut.Lock()
+}
+
+func (s *SafeEValueMap[TKey, TValue]) unlock() {
+	s.mut.Unlock()
+}
+
+func (s *SafeEValueMap[TKey, TValue]) rLock() {
+	s.mut.RLock()
+}
+
+func (s *SafeEValueMap[TKey, TValue]) rUnlock() {
+	s.mut.RUnlock()
+}
+
+// touch resets the timestamp of the value at the specified index.
+// It is safe to call it while holding only the read lock.
+func (s *SafeEValueMap[TKey, TValue]) touch(index int) {
+	atomic.StoreInt64(&s.timestamps[index], time.Now().UnixNano())
+}
+
+// isExpired reports whether the value at the specified index is expired.
+// It is safe to call it while holding only the read lock.
+func (s *SafeEValueMap[TKey, TValue]) isExpired(index int) bool {
+	timestamp := atomic.LoadInt64(&s.timestamps[index])
+	return time.Since(time.Unix(0, timestamp)) > s.expiration
+}
+
+func (s *SafeEValueMap[TKey, TValue]) Exists(key TKey) bool {
+	s.rLock()
+	defer s.rUnlock()
+
+	_, b := s.indexes[key]
+	return b
+}
+
+// Set sets the key of type TKey in this safe map to the value and resets
+// its expiration time.
+func (s *SafeEValueMap[TKey, TValue]) Set(key TKey, value TValue) {
+	s.lock()
+	defer s.unlock()
+
+	if s.disabled {
+		return
+	}
+
+	s.setValue(key, value)
+}
+
+// Add is an alias for Set, kept for consistency with the other map types.
+func (s *SafeEValueMap[TKey, TValue]) Add(key TKey, value TValue) {
+	s.Set(key, value)
+}
+
+func (s *SafeEValueMap[TKey, TValue]) AddList(keyGetter func(*TValue) TKey, elements ...TValue) {
+	if len(elements) == 0 || keyGetter == nil {
+		return
+	}
+
+	s.lock()
+	defer s.unlock()
+
+	if s.disabled {
+		return
+	}
+
+	for _, current := range elements {
+		s.setValue(keyGetter(&current), current)
+	}
+}
+
+// setValue replaces the value for an existing key or appends a new key to
+// all of the map's internal slices. The caller must hold the map's write lock.
+func (s *SafeEValueMap[TKey, TValue]) setValue(key TKey, value TValue) int {
+	index, exists := s.indexes[key]
+	if exists {
+		s.values[index] = value
+		s.touch(index)
+		return index
+	}
+
+	s.keys = append(s.keys, key)
+	s.values = append(s.values, value)
+	s.timestamps = append(s.timestamps, time.Now().UnixNano())
+
+	index = len(s.keys) - 1
+	s.indexes[key] = index
+	return index
+}
+
+// Get returns the value of the key and whether it exists in the map or not.
+// Getting an existing value resets its expiration time.
+func (s *SafeEValueMap[TKey, TValue]) Get(key TKey) (TValue, bool) {
+	s.rLock()
+	defer s.rUnlock()
+
+	index, exists := s.indexes[key]
+	if !exists {
+		return s.defaultValue, false
+	}
+
+	s.touch(index)
+	return s.values[index], true
+}
+
+// GetValue returns the value of the key, or the default value of the map
+// if the key doesn't exist.
+// Getting an existing value resets its expiration time.
+func (s *SafeEValueMap[TKey, TValue]) GetValue(key TKey) TValue {
+	value, _ := s.Get(key)
+	return value
+}
+
+// GetOrCreate returns the value of the key if it exists and is not expired,
+// otherwise it creates a new value using the provided createFn function and
+// adds it to the map. An expired value is kept (and its time is reset) if the
+// pre-expiring condition function of the map returns false for it.
+// If the map is disabled, or createFn returns false, the default value of the map
+// is returned and nothing is stored.
+// createFn runs while the map is locked; re-using this map inside of it will
+// result in a deadlock.
+func (s *SafeEValueMap[TKey, TValue]) GetOrCreate(
+	key TKey,
+	createFn func() (value TValue, ok bool),
+) TValue {
+	value, found := func() (TValue, bool) {
+		s.rLock()
+		defer s.rUnlock()
+
+		index, exists := s.indexes[key]
+		if !exists || s.isExpired(index) {
+			return s.defaultValue, false
+		}
+
+		s.touch(index)
+		return s.values[index], true
+	}()
+	if found {
+		return value
+	}
+
+	s.lock()
+	defer s.unlock()
+
+	index, exists := s.indexes[key]
+	if exists {
+		expired := s.isExpired(index)
+		if !expired || s.disabled ||
+			(s.preExpiringConditionFn != nil &&
+				!s.preExpiringConditionFn(key, s.values[index])) {
+			s.touch(index)
+			return s.values[index]
+		}
+	}
+
+	if s.disabled || createFn == nil {
+		return s.defaultValue
+	}
+
+	value, ok := createFn()
+	if !ok {
+		return s.defaultValue
+	}
+
+	s.setValue(key, value)
+	return value
+}
+
+// Update atomically replaces the value of the key with the value returned
+// from updateFn, resets its expiration time and returns the new value.
+// updateFn receives the current value (or the default value of the map) and
+// whether the key exists.
+// If the map is disabled, updateFn is not called and the current value is returned.
+// updateFn runs while the map is locked; re-using this map inside of it will
+// result in a deadlock.
+func (s *SafeEValueMap[TKey, TValue]) Update(
+	key TKey,
+	updateFn func(value TValue, exists bool) TValue,
+) TValue {
+	s.lock()
+	defer s.unlock()
+
+	value := s.defaultValue
+	index, exists := s.indexes[key]
+	if exists {
+		value = s.values[index]
+	}
+
+	if s.disabled || updateFn == nil {
+		return value
+	}
+
+	value = updateFn(value, exists)
+	s.setValue(key, value)
+	return value
+}
+
+func (s *SafeEValueMap[TKey, TValue]) delete(key TKey, useLock bool) {
+	if useLock {
+		s.lock()
+		defer s.unlock()
+	}
+
+	if s.disabled {
+		return
+	}
+
+	index, exists := s.indexes[key]
+	if !exists {
+		// item does not exist
+		return
+	}
+
+	s.removeAt(index)
+}
+
+// removeAt removes the entry at the specified index by moving the last entry
+// to its position. The caller must hold the map's write lock.
+func (s *SafeEValueMap[TKey, TValue]) removeAt(index int) {
+	lastIndex := len(s.keys) - 1
+	delete(s.indexes, s.keys[index])
+
+	if index != lastIndex {
+		s.keys[index] = s.keys[lastIndex]
+		s.values[index] = s.values[lastIndex]
+		s.timestamps[index] = s.timestamps[lastIndex]
+		s.indexes[s.keys[index]] = index
+	}
+
+	// clear the last slot so the removed key and value can be collected.
+	var zeroKey TKey
+	var zeroValue TValue
+	s.keys[lastIndex] = zeroKey
+	s.values[lastIndex] = zeroValue
+
+	s.keys = s.keys[:lastIndex]
+	s.values = s.values[:lastIndex]
+	s.timestamps = s.timestamps[:lastIndex]
+}
+
+func (s *SafeEValueMap[TKey, TValue]) Delete(key TKey) {
+	s.delete(key, true)
+}
+
+// DeleteIf deletes key when condFn returns true for its value.
+// condFn runs while the map is locked; re-using this map inside condFn will
+// result in a deadlock.
+func (s *SafeEValueMap[TKey, TValue]) DeleteIf(key TKey, condFn func(TValue) bool) {
+	if condFn == nil {
+		return
+	}
+
+	s.lock()
+	defer s.unlock()
+
+	if s.disabled {
+		return
+	}
+
+	index, exists := s.indexes[key]
+	if !exists {
+		return
+	}
+
+	if condFn(s.values[index]) {
+		s.removeAt(index)
+	}
+}
+
+// ForEach calls fn for each entry while holding the map's write lock, and
+// resets the expiration time of every visited entry.
+// The callback must not call another method on this map or wait for a goroutine
+// that does so, because the callback would prevent the lock from being released
+// and cause a deadlock. Use the returned ForEachOperation to remove the current
+// entry or stop the iteration.
+func (s *SafeEValueMap[TKey, TValue]) ForEach(fn func(TKey, TValue) ForEachOperation) {
+	if fn == nil {
+		return
+	}
+	s.lock()
+	defer s.unlock()
+
+	// iterate backwards, so removing the current entry (which moves the
+	// last entry into its place) doesn't skip any of the entries.
+myFor:
+	for i := len(s.keys) - 1; i >= 0; i-- {
+		s.touch(i)
+		switch fn(s.keys[i], s.values[i]) {
+		case ForEachOperationContinue:
+			continue
+		case ForEachOperationBreak:
+			break myFor
+		case ForEachOperationRemove:
+			if !s.disabled {
+				s.removeAt(i)
+			}
+		case ForEachOperationRemoveBreak:
+			if !s.disabled {
+				s.removeAt(i)
+			}
+			break myFor
+		}
+	}
+}
+
+func (s *SafeEValueMap[TKey, TValue]) GetRandomValue() TValue {
+	s.rLock()
+	defer s.rUnlock()
+
+	if len(s.keys) == 0 {
+		return s.defaultValue
+	}
+
+	randomIndex := rand.Intn(len(s.keys))
+	s.touch(randomIndex)
+	return s.values[randomIndex]
+}
+
+func (s *SafeEValueMap[TKey, TValue]) GetRandomKey() (key TKey, ok bool) {
+	s.rLock()
+	defer s.rUnlock()
+
+	if len(s.keys) == 0 {
+		return
+	}
+
+	ok = true
+	key = s.keys[rand.Intn(len(s.keys))]
+	return
+}
+
+func (s *SafeEValueMap[TKey, TValue]) ToArray() []TValue {
+	s.rLock()
+	defer s.rUnlock()
+
+	array := make([]TValue, len(s.values))
+	copy(array, s.values)
+	return array
+}
+
+func (s *SafeEValueMap[TKey, TValue]) ToNormalMap() map[TKey]TValue {
+	s.rLock()
+	defer s.rUnlock()
+
+	m := make(map[TKey]TValue, len(s.keys))
+	for i, key := range s.keys {
+		m[key] = s.values[i]
+	}
+
+	return m
+}
+
+func (s *SafeEValueMap[TKey, TValue]) SetDefault(value TValue) {
+	s.lock()
+	defer s.unlock()
+
+	s.defaultValue = value
+}
+
+// Clear will clear the whole map.
+func (s *SafeEValueMap[TKey, TValue]) Clear() {
+	s.lock()
+	defer s.unlock()
+
+	if s.disabled {
+		return
+	}
+
+	s.indexes = make(map[TKey]int)
+	s.keys = nil
+	s.values = nil
+	s.timestamps = nil
+}
+
+func (s *SafeEValueMap[TKey, TValue]) Length() int {
+	s.rLock()
+	defer s.rUnlock()
+
+	return len(s.keys)
+}
+
+func (s *SafeEValueMap[TKey, TValue]) IsEmpty() bool {
+	return s.Length() == 0
+}
+
+func (s *SafeEValueMap[TKey, TValue]) IsThreadSafe() bool {
+	return true
+}
+
+func (s *SafeEValueMap[TKey, TValue]) IsValid() bool {
+	if s == nil || s.mut == nil {
+		return false
+	}
+
+	s.rLock()
+	defer s.rUnlock()
+
+	return s.indexes != nil && s.hasValidTimings()
+}
+
+// IsDisabled reports whether the map's entries are frozen. A disabled map
+// remains readable, but its entries cannot be added, replaced, or removed.
+// Expiration checks also leave entries untouched while the map is disabled.
+func (s *SafeEValueMap[TKey, TValue]) IsDisabled() bool {
+	s.rLock()
+	defer s.rUnlock()
+
+	return s.disabled
+}
+
+// Disable freezes the map's entries. Existing entries remain readable, but
+// calls that would add, replace, delete, clear, or expire entries have no effect
+// until Enable is called.
+func (s *SafeEValueMap[TKey, TValue]) Disable() {
+	s.lock()
+	defer s.unlock()
+
+	s.disabled = true
+}
+
+// Enable unfreezes the map, allowing its entries to be modified again.
+func (s *SafeEValueMap[TKey, TValue]) Enable() {
+	s.lock()
+	defer s.unlock()
+
+	s.disabled = false
+}
+
+func (s *SafeEValueMap[TKey, TValue]) hasValidTimings() bool {
+	return s.expiration > time.Microsecond && s.checkInterval > time.Second
+}
+
+func (s *SafeEValueMap[TKey, TValue]) EnableChecking() {
+	s.lock()
+	defer s.unlock()
+
+	s.checkingEnabled = true
+
+	if s.isInCheckLoop {
+		return
+	}
+
+	s.isInCheckLoop = true
+	go s.checkLoop()
+}
+
+func (s *SafeEValueMap[TKey, TValue]) DisableChecking() {
+	s.lock()
+	defer s.unlock()
+
+	s.checkingEnabled = false
+}
+
+func (s *SafeEValueMap[TKey, TValue]) IsChecking() bool {
+	s.rLock()
+	defer s.rUnlock()
+
+	return s.checkingEnabled
+}
+
+func (s *SafeEValueMap[TKey, TValue]) SetExpiration(duration time.Duration) {
+	s.lock()
+	defer s.unlock()
+
+	s.expiration = duration
+}
+
+func (s *SafeEValueMap[TKey, TValue]) SetInterval(duration time.Duration) {
+	s.lock()
+	defer s.unlock()
+
+	s.checkInterval = duration
+}
+
+func (s *SafeEValueMap[TKey, TValue]) SetOnExpired(event func(key TKey, value TValue)) {
+	s.lock()
+	defer s.unlock()
+
+	s.onExpired = event
+}
+
+// SetOnExpiredBatch sets the event function that will be called once per check
+// with all of the values expired during that check; the Value of each entry
+// points to a copy of the expired value. It is called after the per-entry
+// callback in ExpiryDispatchSync mode; in the other modes it runs concurrently
+// with it.
+func (s *SafeEValueMap[TKey, TValue]) SetOnExpiredBatch(
+	event func(entries []ExpiredEntry[TKey, TValue]),
+) {
+	s.lock()
+	defer s.unlock()
+
+	s.onExpiredBatch = event
+}
+
+// SetOnCallbackPanic sets the function that is called when one of the expiry
+// callbacks panics. The panic is always recovered, so it never crashes the
+// process; if no function is set, it is silently dropped.
+// For panics in the batch callback, key is the zero value of TKey.
+func (s *SafeEValueMap[TKey, TValue]) SetOnCallbackPanic(fn func(key TKey, recovered any)) {
+	s.lock()
+	defer s.unlock()
+
+	s.onCallbackPanic = fn
+}
+
+// SetExpiryDispatch sets how the expiry callbacks are invoked, the same way as
+// for SafeEMap. Passing nil restores the default mode, ExpiryDispatchGoroutine.
+// Callbacks already queued in a previous ExpiryDispatchPool still run.
+func (s *SafeEValueMap[TKey, TValue]) SetExpiryDispatch(opts *ExpiryDispatchOptions) {
+	dispatcher := newExpiryDispatcher(opts)
+
+	s.lock()
+	defer s.unlock()
+
+	s.dispatcher = dispatcher
+}
+
+// SetPreExpiringConditionFn sets the condition checked under the map's write lock
+// before an expired value is removed or replaced. Returning false keeps the value.
+// Calling this map's methods from fn will result in a deadlock.
+func (s *SafeEValueMap[TKey, TValue]) SetPreExpiringConditionFn(
+	fn func(key TKey, value TValue) bool,
+) {
+	s.lock()
+	defer s.unlock()
+
+	s.preExpiringConditionFn = fn
+}
+
+// DoCheck iterates over the map and checks for expired variables and removes them.
+// if the expiry callbacks of the map are set, it will call them after the map's
+// lock is released, according to the map's dispatch mode.
+func (s *SafeEValueMap[TKey, TValue]) DoCheck() {
+	callbacks, entries := s.removeExpired()
+	callbacks.dispatch(entries)
+}
+
+// removeExpired removes the expired values from the map, and returns them
+// alongside a snapshot of the expiry callbacks.
+func (s *SafeEValueMap[TKey, TValue]) removeExpired() (
+	*expiryCallbacks[TKey, TValue],
+	[]ExpiredEntry[TKey, TValue],
+) {
+	s.lock()
+	defer s.unlock()
+
+	callbacks := &expiryCallbacks[TKey, TValue]{
+		onExpired:       s.onExpired,
+		onExpiredBatch:  s.onExpiredBatch,
+		onCallbackPanic: s.onCallbackPanic,
+		dispatcher:      s.dispatcher,
+		defaultValue:    s.defaultValue,
+	}
+
+	if s.disabled || len(s.keys) == 0 {
+		return callbacks, nil
+	}
+
+	var entries []ExpiredEntry[TKey, TValue]
+	for i := len(s.keys) - 1; i >= 0; i-- {
+		if !s.isExpired(i) {
+			continue
+		}
+
+		key, value := s.keys[i], s.values[i]
+		if s.preExpiringConditionFn != nil &&
+			!s.preExpiringConditionFn(key, value) {
+			continue
+		}
+
+		s.removeAt(i)
+		if callbacks.hasAny() {
+			entries = append(entries, ExpiredEntry[TKey, TValue]{
+				Key:   key,
+				Value: &value,
+			})
+		}
+	}
+
+	return callbacks, entries
+}
+
+func (s *SafeEValueMap[TKey, TValue]) getCheckStatus() checkAction {
+	if s == nil {
+		return checkActionReturn
+	}
+
+	s.rLock()
+	defer s.rUnlock()
+
+	if !s.checkingEnabled {
+		return checkActionReturn
+	}
+
+	if len(s.keys) == 0 {
+		return checkActionContinue
+	}
+
+	return checkActionNormal
+}
+
+func (s *SafeEValueMap[TKey, TValue]) getCheckInterval() time.Duration {
+	s.rLock()
+	defer s.rUnlock()
+
+	return s.checkInterval
+}
+
+func (s *SafeEValueMap[TKey, TValue]) checkLoop() {
+	defer s.onCheckLoopFinished()
+
+	for {
+		time.Sleep(max(s.getCheckInterval(), time.Microsecond))
+
+		status := s.getCheckStatus()
+		if status == checkActionReturn {
+			return
+		} else if status == checkActionContinue {
+			continue
+		}
+
+		s.DoCheck()
+	}
+}
+
+func (s *SafeEValueMap[TKey, TValue]) onCheckLoopFinished() {
+	s.lock()
+	defer s.unlock()
+
+	s.isInCheckLoop = false
+
+	// EnableChecking may have run while this loop was exiting.
+	if s.checkingEnabled {
+		s.isInCheckLoop = true
+		go s.checkLoop()
+	}
+}
@@ -0,0 +1,246 @@
+package mapUtils
+
+import "context"
+
+// canAcquire reports whether the entry can be locked in the specified mode
+// right now. The caller must hold the registry's mutex.
+func (e *keyedLockEntry) canAcquire(write bool) bool {
+	if write {
+		return !e.writer && e.readers == 0
+	}
+
+	return !e.writer && e.waitingWriters == 0
+}
+
+// hasWaiters reports whether any goroutine is waiting for this entry.
+// The caller must hold the registry's mutex.
+func (e *keyedLockEntry) hasWaiters() bool {
+	holders := e.readers
+	if e.writer {
+		holders++
+	}
+
+	return e.refs > holders
+}
+
+// notify wakes up all of the goroutines waiting for this entry.
+// The caller must hold the registry's mutex.
+func (e *keyedLockEntry) notify() {
+	close(e.changed)
+	e.changed = make(chan struct{})
+}
+
+// acquire locks key in the specified mode. If try is true, it returns false
+// instead of waiting. Otherwise it waits until the lock is acquired or ctx is
+// done, in which case ctx.Err() is returned.
+func (l *keyedLocker[TKey]) acquire(
+	ctx context.Context,
+	key TKey,
+	write, try bool,
+) (bool, error) {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+
+	entry := l.entries[key]
+	if entry == nil {
+		entry = &keyedLockEntry{
+			changed: make(chan struct{}),
+		}
+		l.entries[key] = entry
+	}
+
+	entry.refs++
+	if write {
+		entry.waitingWriters++
+	}
+
+	for {
+		if entry.canAcquire(write) {
+			if write {
+				entry.waitingWriters--
+				entry.writer = true
+			} else {
+				entry.readers++
+			}
+			return true, nil
+		}
+
+		var err error
+		if !try {
+			err = ctx.Err()
+		}
+
+		if try || err != nil {
+			// give up: this goroutine is no longer waiting for the entry.
+			entry.refs--
+			if write {
+				entry.waitingWriters--
+				// readers might have been blocked by this writer only.
+				if entry.hasWaiters() {
+					entry.notify()
+				}
+			}
+			l.removeIfUnused(key, entry)
+			return false, err
+		}
+
+		changed := entry.changed
+		l.mut.Unlock()
+		select {
+		case <-changed:
+		case <-ctx.Done():
+		}
+		l.mut.Lock()
+	}
+}
+
+// release unlocks key in the specified mode. It panics if key is not locked
+// in that mode, just like unlocking an unlocked sync.Mutex.
+func (l *keyedLocker[TKey]) release(key TKey, write bool) {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+
+	entry := l.entries[key]
+	switch {
+	case write && (entry == nil || !entry.writer):
+		panic("mapUtils: unlock of unlocked key")
+	case !write && (entry == nil || entry.readers == 0):
+		panic("mapUtils: runlock of unlocked key")
+	}
+
+	if write {
+		entry.writer = false
+	} else {
+		entry.readers--
+	}
+	entry.refs--
+
+	if entry.hasWaiters() {
+		entry.notify()
+	}
+	l.removeIfUnused(key, entry)
+}
+
+// removeIfUnused removes the entry of key from the registry once nobody holds
+// or waits for it. The caller must hold the registry's mutex.
+func (l *keyedLocker[TKey]) removeIfUnused(key TKey, entry *keyedLockEntry) {
+	if entry.refs == 0 {
+		delete(l.entries, key)
+	}
+}
+
+func (l *keyedLocker[TKey]) length() int {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+
+	return len(l.entries)
+}
+
+func (l *keyedLocker[TKey]) isLocked(key TKey) bool {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+
+	entry := l.entries[key]
+	return entry != nil && (entry.writer || entry.readers != 0)
+}
+
+//---------------------------------------------------------
+
+// Lock locks key. If the key is already locked, Lock blocks until it is unlocked.
+func (m *KeyedMutex[TKey]) Lock(key TKey) {
+	_, _ = m.locker.acquire(context.Background(), key, true, false)
+}
+
+// TryLock tries to lock key and reports whether it succeeded, without blocking.
+func (m *KeyedMutex[TKey]) TryLock(key TKey) bool {
+	ok, _ := m.locker.acquire(context.Background(), key, true, true)
+	return ok
+}
+
+// LockCtx locks key, or returns ctx.Err() if ctx is done before the lock
+// could be acquired. The key is not locked when an error is returned.
+func (m *KeyedMutex[TKey]) LockCtx(ctx context.Context, key TKey) error {
+	_, err := m.locker.acquire(ctx, key, true, false)
+	return err
+}
+
+// Unlock unlocks key. It is a run-time error if key is not locked.
+// The lock of the key is removed from the registry once no goroutine holds
+// or waits for it.
+func (m *KeyedMutex[TKey]) Unlock(key TKey) {
+	m.locker.release(key, true)
+}
+
+// IsLocked reports whether key is currently locked.
+func (m *KeyedMutex[TKey]) IsLocked(key TKey) bool {
+	return m.locker.isLocked(key)
+}
+
+// Length returns the number of keys that are currently locked or waited on.
+func (m *KeyedMutex[TKey]) Length() int {
+	return m.locker.length()
+}
+
+//---------------------------------------------------------
+
+// Lock locks key for writing. If the key is already locked for reading or
+// writing, Lock blocks until the lock is available.
+func (m *KeyedRWMutex[TKey]) Lock(key TKey) {
+	_, _ = m.locker.acquire(context.Background(), key, true, false)
+}
+
+// TryLock tries to lock key for writing and reports whether it succeeded,
+// without blocking.
+func (m *KeyedRWMutex[TKey]) TryLock(key TKey) bool {
+	ok, _ := m.locker.acquire(context.Background(), key, true, true)
+	return ok
+}
+
+// LockCtx locks key for writing, or returns ctx.Err() if ctx is done before
+// the lock could be acquired. The key is not locked when an error is returned.
+func (m *KeyedRWMutex[TKey]) LockCtx(ctx context.Context, key TKey) error {
+	_, err := m.locker.acquire(ctx, key, true, false)
+	return err
+}
+
+// Unlock unlocks key for writing. It is a run-time error if key is not
+// locked for writing.
+func (m *KeyedRWMutex[TKey]) Unlock(key TKey) {
+	m.locker.release(key, true)
+}
+
+// RLock locks key for reading. It blocks while the key is locked for writing
+// or a writer is waiting for it.
+func (m *KeyedRWMutex[TKey]) RLock(key TKey) {
+	_, _ = m.locker.acquire(context.Background(), key, false, false)
+}
+
+// TryRLock tries to lock key for reading and reports whether it succeeded,
+// without blocking.
+func (m *KeyedRWMutex[TKey]) TryRLock(key TKey) bool {
+	ok, _ := m.locker.acquire(context.Background(), key, false, true)
+	return ok
+}
+
+// RLockCtx locks key for reading, or returns ctx.Err() if ctx is done before
+// the lock could be acquired. The key is not locked when an error is returned.
+func (m *KeyedRWMutex[TKey]) RLockCtx(ctx context.Context, key TKey) error {
+	_, err := m.locker.acquire(ctx, key, false, false)
+	return err
+}
+
+// RUnlock undoes a single RLock call. It is a run-time error if key is not
+// locked for reading.
+func (m *KeyedRWMutex[TKey]) RUnlock(key TKey) {
+	m.locker.release(key, false)
+}
+
+// IsLocked reports whether key is currently locked for reading or writing.
+func (m *KeyedRWMutex[TKey]) IsLocked(key TKey) bool {
+	return m.locker.isLocked(key)
+}
+
+// Length returns the number of keys that are currently locked or waited on.
+func (m *KeyedRWMutex[TKey]) Length() int {
+	return m.locker.length()
+}
@@ -0,0 +1,337 @@
+package mapUtils
+
+func (s *SafeValueMap[TKey, TValue]) lock() {
+	s.mut.Lock()
+}
+
+func (s *SafeValueMap[TKey, TValue]) unlock() {
+	s.mut.Unlock()
+}
+
+func (s *SafeValueMap[TKey, TValue]) rLock() {
+	s.mut.RLock()
+}
+
+func (s *SafeValueMap[TKey, TValue]) rUnlock() {
+	s.mut.RUnlock()
+}
+
+func (s *SafeValueMap[TKey, TValue]) Exists(key TKey) bool {
+	s.rLock()
+	defer s.rUnlock()
+
+	_, b := s.values[key]
+	return b
+}
+
+// Set sets the key of type TKey in this safe map to the value.
+func (s *SafeValueMap[TKey, TValue]) Set(key TKey, value TValue) {
+	s.lock()
+	defer s.unlock()
+
+	if s.disabled {
+		return
+	}
+
+	s.values[key] = value
+}
+
+// Add is an alias for Set, kept for consistency with the other map types.
+func (s *SafeValueMap[TKey, TValue]) Add(key TKey, value TValue) {
+	s.Set(key, value)
+}
+
+func (s *SafeValueMap[TKey, TValue]) AddList(keyGetter func(*TValue) TKey, elements ...TValue) {
+	if len(elements) == 0 || keyGetter == nil {
+		return
+	}
+
+	s.lock()
+	defer s.unlock()
+
+	if s.disabled {
+		return
+	}
+
+	for _, current := range elements {
+		s.values[keyGetter(&current)] = current
+	}
+}
+
+// Get returns the value of the key and whether it exists in the map or not.
+func (s *SafeValueMap[TKey, TValue]) Get(key TKey) (TValue, bool) {
+	s.rLock()
+	defer s.rUnlock()
+
+	value, exists := s.values[key]
+	return value, exists
+}
+
+// GetValue returns the value of the key, or the default value of the map
+// if the key doesn't exist.
+func (s *SafeValueMap[TKey, TValue]) GetValue(key TKey) TValue {
+	s.rLock()
+	defer s.rUnlock()
+
+	value, exists := s.values[key]
+	if !exists {
+		return s.defaultValue
+	}
+
+	return value
+}
+
+// GetOrCreate returns the value of the key if it exists, otherwise it creates a new
+// value using the provided createFn function and adds it to the map.
+// If the map is disabled, or createFn returns false, the default value of the map
+// is returned and nothing is stored.
+// createFn runs while the map is locked; re-using this map inside of it will
+// result in a deadlock.
+func (s *SafeValueMap[TKey, TValue]) GetOrCreate(
+	key TKey,
+	createFn func() (value TValue, ok bool),
+) TValue {
+	s.rLock()
+	value, exists := s.values[key]
+	s.rUnlock()
+	if exists {
+		return value
+	}
+
+	s.lock()
+	defer s.unlock()
+
+	if value, exists = s.values[key]; exists {
+		return value
+	}
+
+	if s.disabled || createFn == nil {
+		return s.defaultValue
+	}
+
+	value, ok := createFn()
+	if !ok {
+		return s.defaultValue
+	}
+
+	s.values[key] = value
+	return value
+}
+
+// Update atomically replaces the value of the key with the value returned
+// from updateFn, and returns the new value. updateFn receives the current value
+// (or the default value of the map) and whether the key exists.
+// If the map is disabled, updateFn is not called and the current value is returned.
+// updateFn runs while the map is locked; re-using this map inside of it will
+// result in a deadlock.
+func (s *SafeValueMap[TKey, TValue]) Update(
+	key TKey,
+	updateFn func(value TValue, exists bool) TValue,
+) TValue {
+	s.lock()
+	defer s.unlock()
+
+	value, exists := s.values[key]
+	if !exists {
+		value = s.defaultValue
+	}
+
+	if s.disabled || updateFn == nil {
+		return value
+	}
+
+	value = updateFn(value, exists)
+	s.values[key] = value
+	return value
+}
+
+func (s *SafeValueMap[TKey, TValue]) delete(key TKey, useLock bool) {
+	if useLock {
+		s.lock()
+		defer s.unlock()
+	}
+
+	if s.disabled {
+		return
+	}
+
+	delete(s.values, key)
+}
+
+func (s *SafeValueMap[TKey, TValue]) Delete(key TKey) {
+	s.delete(key, true)
+}
+
+// DeleteIf deletes key when condFn returns true for its value.
+// condFn runs while the map is locked; re-using this map inside condFn will
+// result in a deadlock.
+func (s *SafeValueMap[TKey, TValue]) DeleteIf(key TKey, condFn func(TValue) bool) {
+	if condFn == nil {
+		return
+	}
+
+	s.lock()
+	defer s.unlock()
+
+	if s.disabled {
+		return
+	}
+
+	value, exists := s.values[key]
+	if !exists {
+		return
+	}
+
+	if condFn(value) {
+		s.delete(key, false)
+	}
+}
+
+// ForEach calls fn for each entry while holding the map's write lock.
+// The callback must not call another method on this map or wait for a goroutine
+// that does so, because the callback would prevent the lock from being released
+// and cause a deadlock. Use the returned ForEachOperation to remove the current
+// entry or stop the iteration.
+func (s *SafeValueMap[TKey, TValue]) ForEach(fn func(TKey, TValue) ForEachOperation) {
+	if fn == nil {
+		return
+	}
+	s.lock()
+	defer s.unlock()
+
+myFor:
+	for key, value := range s.values {
+		switch fn(key, value) {
+		case ForEachOperationContinue:
+			continue
+		case ForEachOperationBreak:
+			break myFor
+		case ForEachOperationRemove:
+			s.delete(key, false)
+		case ForEachOperationRemoveBreak:
+			s.delete(key, false)
+			break myFor
+		}
+	}
+}
+
+// ForEachReadOnly calls fn for each entry while holding the map's read lock.
+// The callback must not call another method on this map or wait for a goroutine
+// that does so, because nested locking can deadlock, particularly when a writer
+// is waiting. Remove operations returned by the callback are treated as continue
+// or break operations and do not modify the map.
+func (s *SafeValueMap[TKey, TValue]) ForEachReadOnly(fn func(TKey, TValue) ForEachOperation) {
+	if fn == nil {
+		return
+	}
+	s.rLock()
+	defer s.rUnlock()
+
+myFor:
+	for key, value := range s.values {
+		switch fn(key, value) {
+		case ForEachOperationContinue, ForEachOperationRemove:
+			continue
+		case ForEachOperationBreak, ForEachOperationRemoveBreak:
+			break myFor
+		}
+	}
+}
+
+func (s *SafeValueMap[TKey, TValue]) ToArray() []TValue {
+	s.rLock()
+	defer s.rUnlock()
+
+	result := make([]TValue, 0, len(s.values))
+	for _, v := range s.values {
+		result = append(result, v)
+	}
+
+	return result
+}
+
+func (s *SafeValueMap[TKey, TValue]) ToNormalMap() map[TKey]TValue {
+	s.rLock()
+	defer s.rUnlock()
+
+	normalMap := make(map[TKey]TValue, len(s.values))
+	for k, v := range s.values {
+		normalMap[k] = v
+	}
+
+	return normalMap
+}
+
+func (s *SafeValueMap[TKey, TValue]) SetDefault(value TValue) {
+	s.lock()
+	defer s.unlock()
+
+	s.defaultValue = value
+}
+
+// Clear will clear the whole map.
+func (s *SafeValueMap[TKey, TValue]) Clear() {
+	s.lock()
+	defer s.unlock()
+
+	if s.disabled {
+		return
+	}
+
+	if len(s.values) != 0 {
+		s.values = make(map[TKey]TValue)
+	}
+}
+
+func (s *SafeValueMap[TKey, TValue]) Length() int {
+	s.rLock()
+	defer s.rUnlock()
+
+	return len(s.values)
+}
+
+func (s *SafeValueMap[TKey, TValue]) IsEmpty() bool {
+	return s.Length() == 0
+}
+
+func (s *SafeValueMap[TKey, TValue]) IsThreadSafe() bool {
+	return true
+}
+
+func (s *SafeValueMap[TKey, TValue]) IsValid() bool {
+	if s == nil || s.mut == nil {
+		return false
+	}
+
+	s.rLock()
+	defer s.rUnlock()
+
+	return s.values != nil
+}
+
+// IsDisabled reports whether the map's entries are frozen. A disabled map
+// remains readable, but its entries cannot be added, replaced, or removed.
+func (s *SafeValueMap[TKey, TValue]) IsDisabled() bool {
+	s.rLock()
+	defer s.rUnlock()
+
+	return s.disabled
+}
+
+// Disable freezes the map's entries. Existing entries remain readable, but
+// calls that would add, replace, delete, or clear entries have no effect until
+// Enable is called.
+func (s *SafeValueMap[TKey, TValue]) Disable() {
+	s.lock()
+	defer s.unlock()
+
+	s.disabled = true
+}
+
+// Enable unfreezes the map, allowing its entries to be modified again.
+func (s *SafeValueMap[TKey, TValue]) Enable() {
+	s.lock()
+	defer s.unlock()
+
+	s.disabled = false
+}
@@ -22,4 +22,21 @@ type AdvancedMap[TKey comparable, TValue any] struct {
 	// method when the key is not found. (only for value, not pointers, we would still
 	// return nil for pointers)
 	defaultValue TValue
+
+	// indexes field holds the secondary indexes of the map, by their names.
+	indexes map[string]*advancedIndex[TKey, TValue]
+}
+
+// advancedIndex is a secondary index of an AdvancedMap, mapping the values
+// returned from keyFn to the keys of the map that hold them.
+type advancedIndex[TKey comparable, TValue any] struct {
+	keyFn  func(*TValue) any
+	unique bool
+
+	// entries maps each index value to the set of map keys having it.
+	entries map[any]map[TKey]struct{}
+
+	// indexValues stores the index value of each map key, so the old value can be
+	// removed from entries even if the TValue was modified in place.
+	indexValues map[TKey]any
 }
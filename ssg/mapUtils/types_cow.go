@@ -0,0 +1,23 @@
+package mapUtils
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// CowMap is a copy-on-write map of type TKey to values of type TValue, meant
+// for read-mostly data such as feature flags and routing tables.
+// The current contents are published as an immutable snapshot through an atomic
+// pointer, so readers never take a lock. Writers are serialized with a mutex,
+// clone the current snapshot, apply their changes and swap the new snapshot in;
+// this makes every write O(n), so batch-write APIs (SetMany, DeleteMany, Batch,
+// Replace) should be preferred when changing more than one key.
+type CowMap[TKey comparable, TValue any] struct {
+	// writeMut serializes writers; readers never touch it.
+	writeMut *sync.Mutex
+	snapshot atomic.Pointer[map[TKey]TValue]
+
+	// defaultValue field is the default value this map has to return in GetValue
+	// method when the key is not found.
+	defaultValue atomic.Pointer[TValue]
+}
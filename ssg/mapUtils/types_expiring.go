@@ -1,6 +1,7 @@
 package mapUtils
 
 import (
+	"hash/maphash"
 	"sync"
 	"time"
 )
@@ -43,10 +44,73 @@ type SafeEMap[TKey comparable, TValue any] struct {
 	preExpiringConditionFn func(key TKey, value *TValue) bool
 
 	// onExpired is the event function that will be called when a value with the certain
-	// key on the map is expired. this event function will be called in a new goroutine.
+	// key on the map is expired. by default, this event function will be called in a
+	// new goroutine; see ExpiryDispatchMode.
 	onExpired func(key TKey, value TValue)
 
 	// onExpiredPtr is the event function that will be called when a value with the certain
-	// key on the map is expired. this event function will be called in a new goroutine.
+	// key on the map is expired. by default, this event function will be called in a
+	// new goroutine; see ExpiryDispatchMode.
 	onExpiredPtr func(key TKey, value *TValue)
+
+	// onExpiredBatch is the event function that will be called once per check with
+	// all of the values that were expired during that check.
+	onExpiredBatch func(entries []ExpiredEntry[TKey, TValue])
+
+	// onCallbackPanic is called with the recovered value when one of the expiry
+	// callbacks panics.
+	onCallbackPanic func(key TKey, recovered any)
+
+	// dispatcher determines how the expiry callbacks are invoked.
+	// A nil dispatcher means ExpiryDispatchGoroutine.
+	dispatcher *expiryDispatcher
+}
+
+// ExpiredEntry is a key/value pair removed from a SafeEMap (or a SafeEValueMap)
+// because it was expired.
+type ExpiredEntry[TKey comparable, TValue any] struct {
+	Key   TKey
+	Value *TValue
+}
+
+// ExpiryDispatchMode determines how a SafeEMap (or a SafeEValueMap) invokes its
+// expiry callbacks.
+type ExpiryDispatchMode uint8
+
+// ExpiryDispatchOptions configures how a SafeEMap (or a SafeEValueMap) invokes
+// its expiry callbacks.
+type ExpiryDispatchOptions struct {
+	// Mode is the dispatch mode; the default is ExpiryDispatchGoroutine.
+	Mode ExpiryDispatchMode
+
+	// Workers is the maximum number of goroutines running callbacks at the same
+	// time in ExpiryDispatchPool mode. Values less than 1 mean runtime.NumCPU().
+	Workers int
+}
+
+// expiryDispatcher runs expiry callbacks on a bounded number of workers.
+type expiryDispatcher struct {
+	mode    ExpiryDispatchMode
+	seed    maphash.Seed
+	workers []*expiryWorker
+}
+
+// expiryWorker runs its queued tasks one by one, in order. Its goroutine is
+// started when a task is queued and exits as soon as the queue is empty, so
+// an idle worker doesn't hold a goroutine.
+type expiryWorker struct {
+	mut     *sync.Mutex
+	queue   []func()
+	running bool
+}
+
+// expiryCallbacks is a snapshot of an expiring map's callbacks, taken under
+// the map's lock so that the callbacks can be invoked after it is released.
+type expiryCallbacks[TKey comparable, TValue any] struct {
+	onExpired       func(key TKey, value TValue)
+	onExpiredPtr    func(key TKey, value *TValue)
+	onExpiredBatch  func(entries []ExpiredEntry[TKey, TValue])
+	onCallbackPanic func(key TKey, recovered any)
+	dispatcher      *expiryDispatcher
+	defaultValue    TValue
 }
@@ -0,0 +1,45 @@
+package mapUtils
+
+import "sync"
+
+// keyedLocker is the registry shared by KeyedMutex and KeyedRWMutex.
+// It keeps one entry per key that is currently locked or waited on, and
+// removes the entry as soon as its last holder or waiter leaves.
+type keyedLocker[TKey comparable] struct {
+	mut     *sync.Mutex
+	entries map[TKey]*keyedLockEntry
+}
+
+// keyedLockEntry holds the lock state of a single key.
+// All of its fields are protected by the registry's mutex.
+type keyedLockEntry struct {
+	// refs is the number of holders plus the number of waiters of this entry.
+	// The entry is removed from the registry when it reaches zero.
+	refs int
+
+	readers        int
+	writer         bool
+	waitingWriters int
+
+	// changed is closed (and replaced) whenever the entry is released, to
+	// wake up the goroutines waiting for it.
+	changed chan struct{}
+}
+
+// KeyedMutex is a registry of mutual exclusion locks, one per key.
+// Locks are created on demand and removed automatically once no goroutine
+// holds or waits for them, so it can be used to serialize operations per user
+// or per chat without a global lock and without leaking memory.
+// The zero value is not usable; use NewKeyedMutex to create one.
+type KeyedMutex[TKey comparable] struct {
+	locker *keyedLocker[TKey]
+}
+
+// KeyedRWMutex is a registry of reader/writer locks, one per key.
+// Like KeyedMutex, locks are reference counted and removed automatically once
+// no goroutine holds or waits for them. A waiting writer blocks new readers of
+// the same key, so writers can't be starved.
+// The zero value is not usable; use NewKeyedRWMutex to create one.
+type KeyedRWMutex[TKey comparable] struct {
+	locker *keyedLocker[TKey]
+}
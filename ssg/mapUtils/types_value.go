@@ -0,0 +1,79 @@
+package mapUtils
+
+import (
+	"sync"
+	"time"
+)
+
+// SafeValueMap is a safe map of type TKey to values of type TValue.
+// Unlike SafeMap, the values are stored inline in the map instead of behind
+// a pointer, so adding an entry doesn't allocate a separate TValue on the heap.
+// This makes it a better fit for a large number of small entries (counters,
+// flags, ids, etc).
+// Because the values are copied in and out of the map, TValue must be safe to
+// copy; see the package documentation.
+// this map is completely thread safe and is using internal lock when
+// getting and setting variables.
+type SafeValueMap[TKey comparable, TValue any] struct {
+	mut    *sync.RWMutex
+	values map[TKey]TValue
+
+	// defaultValue field is the default value this map has to return in GetValue
+	// method when the key is not found.
+	defaultValue TValue
+
+	// disabled determines whether the map is disabled or not.
+	disabled bool
+}
+
+// SafeEValueMap is the value-storing variant of SafeEMap.
+// Values are stored inline in a slice and their timestamps are kept in a
+// parallel slice of unix nanoseconds, so there is no ExpiringValue (and no
+// per-entry mutex) allocated for each key.
+// this map is completely thread safe and is using internal lock when
+// getting and setting variables.
+type SafeEValueMap[TKey comparable, TValue any] struct {
+	checkingEnabled bool
+	isInCheckLoop   bool
+
+	checkInterval time.Duration
+	expiration    time.Duration
+	mut           *sync.RWMutex
+
+	// indexes maps each key to its position in the keys, values and
+	// timestamps slices below.
+	indexes map[TKey]int
+	keys    []TKey
+	values  []TValue
+	// timestamps holds the last access time of each value in unix nanoseconds.
+	// Elements are updated atomically, so readers holding the read lock can
+	// refresh them.
+	timestamps []int64
+
+	defaultValue TValue
+
+	// disabled determines whether the map is disabled or not.
+	disabled bool
+
+	// preExpiringConditionFn is called before an expired value is removed.
+	// It can return false to keep the current value.
+	// Calling the map's methods inside of this function will result in a deadlock.
+	preExpiringConditionFn func(key TKey, value TValue) bool
+
+	// onExpired is the event function that will be called when a value with the certain
+	// key on the map is expired. by default, this event function will be called in a
+	// new goroutine; see ExpiryDispatchMode.
+	onExpired func(key TKey, value TValue)
+
+	// onExpiredBatch is the event function that will be called once per check with
+	// all of the values that were expired during that check.
+	onExpiredBatch func(entries []ExpiredEntry[TKey, TValue])
+
+	// onCallbackPanic is called with the recovered value when one of the expiry
+	// callbacks panics.
+	onCallbackPanic func(key TKey, recovered any)
+
+	// dispatcher determines how the expiry callbacks are invoked.
+	// A nil dispatcher means ExpiryDispatchGoroutine.
+	dispatcher *expiryDispatcher
+}
@@ -0,0 +1,7 @@
+package rangeValues
+
+import "errors"
+
+var (
+	ErrInvalidRange = errors.New("invalid range")
+)
@@ -40,3 +40,97 @@ func ParseIntArray[T Integer](value string) []T {
 
 	return myInts
 }
+
+// SplitRange splits a range in the form of "min-max" (such as "1-65535" or
+// "-10--1") into its two parts. It returns false if value is not a range.
+func SplitRange(value string) (string, string, bool) {
+	value = strings.TrimSpace(value)
+
+	// the first character may be the sign of the min value, so the separator
+	// is searched from the second one.
+	index := -1
+	if len(value) > 1 {
+		index = strings.Index(value[1:], "-")
+	}
+	if index == -1 {
+		return "", "", false
+	}
+
+	index++
+	minValue := strings.TrimSpace(value[:index])
+	maxValue := strings.TrimSpace(value[index+1:])
+	if minValue == "" || maxValue == "" {
+		return "", "", false
+	}
+
+	return minValue, maxValue, true
+}
+
+// ParseIntegerRange parses a range in the form of "min-max", such as
+// "1-65535". It returns nil if value is not a valid range.
+func ParseIntegerRange[T Integer](value string) *IntegerRange[T] {
+	minStr, maxStr, ok := SplitRange(value)
+	if !ok {
+		return nil
+	}
+
+	var zero T
+	isSigned := zero-1 < zero
+	parse := func(s string) (T, error) {
+		if isSigned {
+			v, err := strconv.ParseInt(s, 10, 64)
+			return T(v), err
+		}
+
+		v, err := strconv.ParseUint(s, 10, 64)
+		return T(v), err
+	}
+
+	minValue, err := parse(minStr)
+	if err != nil {
+		return nil
+	}
+
+	maxValue, err := parse(maxStr)
+	if err != nil || maxValue < minValue {
+		return nil
+	}
+
+	return &IntegerRange[T]{
+		Min: minValue,
+		Max: maxValue,
+	}
+}
+
+func formatInteger[T Integer](value T) string {
+	var zero T
+	if zero-1 < zero {
+		return strconv.FormatInt(int64(value), 10)
+	}
+
+	return strconv.FormatUint(uint64(value), 10)
+}
+
+// ParseRangeFloat64 parses a range in the form of "min-max", such as
+// "0.5-1.5". It returns nil if value is not a valid range.
+func ParseRangeFloat64(value string) *RangeFloat64 {
+	minStr, maxStr, ok := SplitRange(value)
+	if !ok {
+		return nil
+	}
+
+	minValue, err := strconv.ParseFloat(minStr, 64)
+	if err != nil {
+		return nil
+	}
+
+	maxValue, err := strconv.ParseFloat(maxStr, 64)
+	if err != nil || maxValue < minValue {
+		return nil
+	}
+
+	return &RangeFloat64{
+		Min: minValue,
+		Max: maxValue,
+	}
+}
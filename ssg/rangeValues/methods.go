@@ -3,6 +3,7 @@ package rangeValues
 import (
 	"math"
 	"math/rand"
+	"strconv"
 )
 
 //---------------------------------------------------------
@@ -24,6 +25,22 @@ func (r *IntegerRange[T]) GetRandom() T {
 	return T(rand.Int63n(int64(r.Max-r.Min)) + int64(r.Min))
 }
 
+// MarshalText implements encoding.TextMarshaler, in the form of "min-max".
+func (r *IntegerRange[T]) MarshalText() ([]byte, error) {
+	return []byte(formatInteger(r.Min) + "-" + formatInteger(r.Max)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler; see ParseIntegerRange.
+func (r *IntegerRange[T]) UnmarshalText(text []byte) error {
+	parsed := ParseIntegerRange[T](string(text))
+	if parsed == nil {
+		return ErrInvalidRange
+	}
+
+	*r = *parsed
+	return nil
+}
+
 //---------------------------------------------------------
 
 func (r *RangeFloat64) IsInRange(value float64) bool {
@@ -38,6 +55,22 @@ func (r *RangeFloat64) IsNaN() bool {
 	return math.IsNaN(r.Min) || math.IsNaN(r.Max)
 }
 
+// MarshalText implements encoding.TextMarshaler, in the form of "min-max".
+func (r *RangeFloat64) MarshalText() ([]byte, error) {
+	return []byte(strconv.FormatFloat(r.Min, 'g', -1, 64) + "-" + strconv.FormatFloat(r.Max, 'g', -1, 64)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler; see ParseRangeFloat64.
+func (r *RangeFloat64) UnmarshalText(text []byte) error {
+	parsed := ParseRangeFloat64(string(text))
+	if parsed == nil {
+		return ErrInvalidRange
+	}
+
+	*r = *parsed
+	return nil
+}
+
 func (r *RangeFloat64) IsValueInRange(value *RangeFloat64) bool {
 	if value == nil || r.IsNaN() {
 		return false
@@ -1,5 +1,95 @@
 package strongParser
 
+import "time"
+
 const (
 	defaultSectionName = "DEFAULT"
+	includeSectionName = "include"
+	includeOptionName  = "include"
+)
+
+const (
+	defaultWatchInterval = time.Second
+	defaultWatchDebounce = 100 * time.Millisecond
+)
+
+const (
+	// DefaultSecretKeyEnv is the environment variable that holds the key of
+	// encrypted values, unless ConfigParserOptions.SecretKeyEnv is set.
+	DefaultSecretKeyEnv = "CONFIG_SECRET_KEY"
+)
+
+const (
+	jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+)
+
+const (
+	secretFilePrefix     = "file:"
+	encryptedValuePrefix = "enc:"
+	secretFileEnvSuffix  = "_FILE"
+	secretMask           = "******"
+)
+
+const (
+	// ParseErrorMalformedLine is a line that is neither a comment, a section
+	// header nor an option.
+	ParseErrorMalformedLine ParseErrorKind = iota + 1
+
+	// ParseErrorMissingSection is an option before the first section header.
+	ParseErrorMissingSection
+
+	// ParseErrorUnterminatedSection is a section header without its closing
+	// bracket.
+	ParseErrorUnterminatedSection
+
+	// ParseErrorDuplicateSection is a section header that appears more than
+	// once in the same source.
+	ParseErrorDuplicateSection
+
+	// ParseErrorUnterminatedValue is a triple-quoted value that is not
+	// closed before the end of the source.
+	ParseErrorUnterminatedValue
+
+	// ParseErrorInvalidDocument is content that is not valid in the format
+	// of the source, such as invalid JSON; Err describes the problem.
+	ParseErrorInvalidDocument
+)
+
+const (
+	// SourceFile is a value of the config file (or of a file it includes).
+	SourceFile SourceKind = iota + 1
+
+	// SourceEnv is a value of an environment variable (or of the DotEnvFiles
+	// option).
+	SourceEnv
+
+	// SourceDefault is the `default` tag of the field.
+	SourceDefault
+
+	// SourceFlag is a command-line flag (see ParseConfigWithFlags).
+	SourceFlag
+)
+
+const (
+	// fieldValue is a field filled from a single option.
+	fieldValue fieldKind = iota
+
+	// fieldMap is a map field filled from all of the options of a section.
+	fieldMap
+
+	// fieldStructMap is a map field whose elements are structs, filled from
+	// the sections named "section.name".
+	fieldStructMap
+
+	// fieldStructSlice is a slice field whose elements are structs, filled
+	// from the sections that match its section name (see getSliceSections).
+	fieldStructSlice
+)
+
+const (
+	// configLineRaw is a comment, a blank line or a line that couldn't be
+	// parsed; it's written back as is.
+	configLineRaw configLineKind = iota
+	configLineSection
+	configLineOption
 )
@@ -5,4 +5,20 @@ import "errors"
 var (
 	ErrInvalidBoolValue = errors.New("invalid bool value")
 	ErrEmptyStringValue = errors.New("empty string value")
+
+	ErrInterpolationCycle   = errors.New("interpolation cycle")
+	ErrInvalidInterpolation = errors.New("invalid interpolation syntax")
+
+	ErrIncludeCycle = errors.New("include cycle")
+
+	ErrTypeMismatch      = errors.New("type mismatch")
+	ErrUnknownKey        = errors.New("unknown key")
+	ErrDuplicateKey      = errors.New("duplicate key")
+	ErrKeyOutsideSection = errors.New("key outside of any section")
+
+	ErrNoSecretKey      = errors.New("no secret key to decrypt the value")
+	ErrInvalidSecretKey = errors.New("invalid secret key")
+	ErrInvalidSecret    = errors.New("invalid encrypted value")
+
+	errWatchNotSupported = errors.New("file watching is not supported")
 )
@@ -1,8 +1,11 @@
 package strongParser
 
 import (
-	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"reflect"
 	"strconv"
@@ -47,69 +50,89 @@ func NewConfigParserFromFile(filename string) (*ConfigParser, error) {
 
 // Parse takes a filename and parses it into a ConfigParser value.
 func Parse(filename string) (*ConfigParser, error) {
-	virtualFlag := strings.Contains(filename, ":virtual")
-	if virtualFlag {
-		filename = strings.ReplaceAll(filename, ":virtual", "")
-	}
+	return ParseWithOption(filename, nil)
+}
 
-	file, err := os.Open(filename)
-	if err != nil {
-		if virtualFlag {
+// ParseWithOption takes a filename and parses it into a ConfigParser value,
+// using the syntax options of opt (such as MultilineValues); opt is also used
+// by Get and the other methods of the returned ConfigParser.
+//
+// A ":virtual" suffix in filename is the same as the Optional option; it's
+// kept for compatibility.
+func ParseWithOption(filename string, opt *ConfigParserOptions) (*ConfigParser, error) {
+	if strings.Contains(filename, ":virtual") {
+		filename = strings.ReplaceAll(filename, ":virtual", "")
+		if _, err := os.Stat(filename); err != nil {
 			// don't complain on virtual file
-			return parseString("")
+			return parseString("", opt)
 		}
-
-		return nil, err
 	}
 
-	defer func() {
-		if file != nil {
-			_ = file.Close()
-		}
-	}()
-	p, err := parseFile(file)
-	if err != nil {
-		return nil, err
-	}
-	return p, nil
+	return parseLayers(nil, []string{filename}, opt)
 }
 
 // ParseBytes takes bytes array and parses it into a ConfigParser value.
 func ParseBytes(b []byte) (*ConfigParser, error) {
-	return parseBytes(b)
+	return parseBytes(b, nil)
 }
 
 // ParseBytes takes bytes array and parses it into a ConfigParser value.
 func ParseString(value string) (*ConfigParser, error) {
-	return parseString(value)
+	return parseString(value, nil)
+}
+
+// ParseStringWithOption parses value into a ConfigParser value; see
+// ParseWithOption.
+func ParseStringWithOption(value string, opt *ConfigParserOptions) (*ConfigParser, error) {
+	return parseString(value, opt)
 }
 
 func ParseConfig(value any, filename string) error {
 	return ParseConfigWithOption(value, filename, nil)
 }
 
+// ParseConfigWithOption parses the named file into value, using opt (see
+// ParseWithOption). If the file doesn't exist and opt.ReadEnv is set, value
+// is filled from the environment and the default tags only; any other error
+// (such as a syntax error or a missing included file) is returned.
 func ParseConfigWithOption(value any, filename string, opt *ConfigParserOptions) error {
-	p, err := Parse(filename)
+	p, err := ParseWithOption(filename, opt)
 	if err != nil {
-		if opt == nil || !opt.ReadEnv {
+		p, err = getEnvOnlyParser(err, opt)
+		if err != nil {
 			return err
 		}
-
-		p = NewConfigParser()
 	}
 
 	p.options = opt
 	return parseFinalConfig(value, "", p)
 }
 
+// getEnvOnlyParser returns an empty ConfigParser, so that values are read
+// from the environment and the default tags only, if err is returned for a
+// config file that doesn't exist and opt.ReadEnv is set; otherwise it
+// returns err.
+func getEnvOnlyParser(err error, opt *ConfigParserOptions) (*ConfigParser, error) {
+	if opt == nil || !opt.ReadEnv {
+		return nil, err
+	}
+
+	// the errors of included files are wrapped with the path of the file
+	// that includes them, so only the file itself gives a *fs.PathError.
+	if pathErr, ok := err.(*fs.PathError); !ok || !errors.Is(pathErr, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	return NewConfigParser(), nil
+}
+
 func ParseMainAndArrays[mT any, aT any](filename string, opt *ConfigParserOptions) (*MainAndArrayContainer[mT, aT], error) {
-	p, err := Parse(filename)
+	p, err := ParseWithOption(filename, opt)
 	if err != nil {
-		if opt == nil || !opt.ReadEnv {
+		p, err = getEnvOnlyParser(err, opt)
+		if err != nil {
 			return nil, err
 		}
-
-		p = NewConfigParser()
 	}
 
 	p.options = opt
@@ -117,13 +140,12 @@ func ParseMainAndArrays[mT any, aT any](filename string, opt *ConfigParserOption
 }
 
 func ParseMainAndArraysStr[mT any, aT any](valueStr string, opt *ConfigParserOptions) (*MainAndArrayContainer[mT, aT], error) {
-	p, err := parseString(valueStr)
+	p, err := parseString(valueStr, opt)
 	if err != nil {
-		if opt == nil || !opt.ReadEnv {
+		p, err = getEnvOnlyParser(err, opt)
+		if err != nil {
 			return nil, err
 		}
-
-		p = NewConfigParser()
 	}
 
 	p.options = opt
@@ -132,6 +154,9 @@ func ParseMainAndArraysStr[mT any, aT any](valueStr string, opt *ConfigParserOpt
 
 func parseMainAndArrays[mT any, aT any](p *ConfigParser) (*MainAndArrayContainer[mT, aT], error) {
 	var err error
+	// validation errors of all sections are reported together, after the
+	// problems of the source (which are the same for all of them).
+	violations := append(ValidationErrors(nil), p.sourceViolations...)
 
 	if p.options.MainSectionName == "" {
 		p.options.MainSectionName = DefaultMainSection
@@ -145,19 +170,22 @@ func parseMainAndArrays[mT any, aT any](p *ConfigParser) (*MainAndArrayContainer
 			continue
 		} else if current.Name == p.options.MainSectionName {
 			err = parseFinalConfig(container.Main, current.Name, p)
-			if err != nil {
+			if err != nil && !errors.As(err, new(ValidationErrors)) {
 				return nil, err
 			}
 
+			violations = append(violations, p.violations...)
 			continue
 		}
 
 		var currentSection = new(aT)
 		err = parseFinalConfig(currentSection, current.Name, p)
-		if err != nil {
+		if err != nil && !errors.As(err, new(ValidationErrors)) {
 			return nil, err
 		}
 
+		violations = append(violations, p.violations...)
+
 		validS, ok := interface{}(currentSection).(SectionValue)
 		if ok {
 			validS.SetSectionName(current.Name)
@@ -166,11 +194,15 @@ func parseMainAndArrays[mT any, aT any](p *ConfigParser) (*MainAndArrayContainer
 		container.Sections = append(container.Sections, currentSection)
 	}
 
+	if len(violations) != 0 {
+		return nil, violations
+	}
+
 	return container, nil
 }
 
 func ParseByteConfig(value any, b []byte) error {
-	p, err := parseBytes(b)
+	p, err := parseBytes(b, nil)
 	if err != nil {
 		return err
 	}
@@ -179,7 +211,7 @@ func ParseByteConfig(value any, b []byte) error {
 }
 
 func ParseStringConfig(value any, strValue string) error {
-	p, err := parseString(strValue)
+	p, err := parseString(strValue, nil)
 	if err != nil {
 		return err
 	}
@@ -188,7 +220,7 @@ func ParseStringConfig(value any, strValue string) error {
 }
 
 func ParseStringConfigWithOption(value any, strValue string, opt *ConfigParserOptions) error {
-	p, err := parseString(strValue)
+	p, err := parseString(strValue, opt)
 	if err != nil {
 		return err
 	}
@@ -207,188 +239,432 @@ func parseFinalConfig(v any, section string, configValue *ConfigParser) error {
 	}
 
 	rv := reflect.ValueOf(v)
-	myType := reflect.TypeOf(v)
 	if rv.Kind() != reflect.Ptr || rv.IsNil() {
 		return &InvalidParseError{reflect.TypeOf(v)}
 	}
 
-	rv = rv.Elem()
-	myType = myType.Elem()
+	// nested structs of the main section (or of the root struct) are mapped
+	// to top-level sections; nested structs of any other section are mapped
+	// to dotted sections under it.
+	parentSection := section
+	if parentSection == configValue.options.MainSectionName {
+		parentSection = ""
+	}
 
-	var currentField reflect.Value
-	var shouldSkipCounter bool
-	var currentIndex = -1
+	err := configValue.loadDotEnv()
+	if err != nil {
+		return err
+	}
 
-	for {
-		if !shouldSkipCounter {
-			currentIndex++
-			if currentIndex >= rv.NumField() {
-				break
+	if configValue.flatKeys {
+		configValue.mapFlatKeys(rv.Elem().Type(), section, parentSection)
+	}
+
+	configValue.violations = nil
+	configValue.knownKeys = nil
+	err = configValue.parseStruct(rv.Elem(), section, parentSection)
+	if err != nil {
+		return err
+	}
+
+	configValue.checkUnknownKeys()
+	if violations := configValue.getViolations(); len(violations) != 0 {
+		return violations
+	}
+
+	return nil
+}
+
+// parseStruct fills the exported fields of the struct value rv, and validates
+// them (see validateField).
+// section is the section its fields have to be read from (if empty, the
+// `section` tag of each field or the main section is used), and parentSection
+// is the section name that nested structs are placed under.
+func (p *ConfigParser) parseStruct(rv reflect.Value, section, parentSection string) error {
+	err := p.parseFields(rv, section, parentSection)
+	if err != nil {
+		return err
+	}
+
+	p.validateStruct(rv, section)
+	return nil
+}
+
+// parseFields fills and validates the fields of rv like parseStruct, without
+// running its Validator. The fields of embedded structs are filled as if they
+// were fields of rv (see isEmbeddedStruct).
+func (p *ConfigParser) parseFields(rv reflect.Value, section, parentSection string) error {
+	myType := rv.Type()
+	for currentIndex := 0; currentIndex < rv.NumField(); currentIndex++ {
+		currentField := rv.Field(currentIndex)
+		fByName := myType.Field(currentIndex)
+		if isEmbeddedStruct(fByName) {
+			err := p.parseEmbeddedStruct(currentField, section, parentSection)
+			if err != nil {
+				return err
 			}
-			currentField = rv.Field(currentIndex)
-		} else {
-			shouldSkipCounter = false
+
+			continue
 		}
 
-		if !currentField.CanSet() {
+		if !currentField.CanSet() || !fByName.IsExported() {
 			// ignore it
 			continue
 		}
 
-		switch currentField.Kind() {
+		found, err := p.parseField(currentField, fByName, section, parentSection)
+		if err != nil {
+			return err
+		}
+
+		p.validateField(currentField, fByName, section, parentSection, found)
+	}
+
+	return nil
+}
+
+// parseEmbeddedStruct fills the fields of an embedded struct (or pointer to
+// one) as fields of the struct that embeds it. A nil pointer is only
+// allocated when any of its fields got a value.
+func (p *ConfigParser) parseEmbeddedStruct(currentField reflect.Value, section, parentSection string) error {
+	if currentField.Kind() != reflect.Ptr {
+		return p.parseFields(currentField, section, parentSection)
+	}
+
+	if !currentField.IsNil() {
+		return p.parseFields(currentField.Elem(), section, parentSection)
+	}
+
+	if !currentField.CanSet() {
+		// a nil pointer to an unexported type.
+		return nil
+	}
+
+	newValue := reflect.New(currentField.Type().Elem())
+	err := p.parseFields(newValue.Elem(), section, parentSection)
+	if err != nil {
+		return err
+	}
+
+	if !newValue.Elem().IsZero() {
+		currentField.Set(newValue)
+	}
+
+	return nil
+}
+
+// isEmbeddedStruct returns true if fByName is an embedded struct (or pointer
+// to one) without a `section` tag, whose fields are promoted to the struct
+// that embeds it: they are read from the same section as its other fields.
+// Embedded structs with a `section` tag are mapped to their own section, like
+// named nested structs.
+func isEmbeddedStruct(fByName reflect.StructField) bool {
+	if !fByName.Anonymous || fByName.Tag.Get("section") != "" {
+		return false
+	}
+
+	t := fByName.Type
+	if t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Ptr {
+		return false
+	}
+
+	return isStructType(t)
+}
+
+// parseField fills a single struct field, described by fByName, and returns
+// true if a value was found for it (for nested structs: if their section
+// exists).
+func (p *ConfigParser) parseField(
+	currentField reflect.Value,
+	fByName reflect.StructField,
+	section, parentSection string,
+) (bool, error) {
+	if getDecoder(currentField.Type()) != nil {
+		// types with a decoder are a single value, even if they are structs.
+		return p.parseValue(currentField, fByName, section)
+	}
+
+	switch currentField.Kind() {
+	case reflect.Struct:
+		nestedSection := getNestedSectionName(fByName, parentSection)
+		err := p.parseStruct(currentField, nestedSection, nestedSection)
+		return p.hasSectionOrSubSection(nestedSection), err
+	case reflect.Map:
+		return p.parseMap(currentField, fByName, parentSection)
+	case reflect.Slice:
+		if isStructType(currentField.Type().Elem()) {
+			return p.parseStructSlice(currentField, fByName, parentSection)
+		}
+	case reflect.Ptr:
+		elemType := currentField.Type().Elem()
+		switch elemType.Kind() {
 		case reflect.Struct:
-			continue
+			err := p.parseStructPointer(currentField, fByName, parentSection)
+			return !currentField.IsNil(), err
 		case reflect.Ptr:
-			fByName := myType.Field(currentIndex)
-			if !fByName.IsExported() {
-				continue
-			}
+			return false, nil
+		}
 
-			myKind := GetPointerKind(fByName.Type)
+		if !currentField.IsNil() {
+			return p.parseValue(currentField.Elem(), fByName, section)
+		}
 
-			if myKind == reflect.Invalid || myKind == reflect.Struct {
-				continue
-			}
+		newValue := reflect.New(elemType)
+		found, err := p.parseValue(newValue.Elem(), fByName, section)
+		if found {
+			currentField.Set(newValue)
+		} else if !p.options.KeepNilPointers {
+			SetDefaultValue(currentField, GetPointerKind(currentField.Type()))
+		}
 
-			SetDefaultValue(currentField, myKind)
-			currentField = currentField.Elem()
-			shouldSkipCounter = true
-			continue
-		case reflect.String:
-			fByName := myType.Field(currentIndex)
-			if !fByName.IsExported() {
-				continue
-			}
+		return found, err
+	}
 
-			strValue := extractFieldValue(
-				configValue,
-				myType,
-				currentIndex,
-				section,
-				extractStr,
-			)
-			if strValue != "" {
-				currentField.SetString(strValue)
-			}
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			fByName := myType.Field(currentIndex)
-			if !fByName.IsExported() {
-				continue
-			}
+	return p.parseValue(currentField, fByName, section)
+}
 
-			intValue := extractFieldValue(
-				configValue,
-				myType,
-				currentIndex,
-				section,
-				extractInt64,
-			)
-			if intValue != 0 {
-				currentField.SetInt(intValue)
-			}
-		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			fByName := myType.Field(currentIndex)
-			if !fByName.IsExported() {
-				continue
-			}
+// getFieldLocation returns the section and the key a non-struct field is read
+// from.
+func (p *ConfigParser) getFieldLocation(fByName reflect.StructField, section string) (string, string) {
+	return getFieldLocation(fByName, section, p.options.MainSectionName)
+}
 
-			uintValue := extractFieldValue(
-				configValue,
-				myType,
-				currentIndex,
-				section,
-				extractUInt64,
-			)
-			if uintValue != 0 {
-				currentField.SetUint(uintValue)
-			}
-		case reflect.Bool:
-			fByName := myType.Field(currentIndex)
-			if !fByName.IsExported() {
-				continue
-			}
+// getFieldLocation returns the section and the key a non-struct field is read
+// from, given the name of the main section.
+func getFieldLocation(fByName reflect.StructField, section, mainSection string) (string, string) {
+	if section == "" {
+		section = fByName.Tag.Get("section")
+	}
 
-			boolValue := extractFieldValue(
-				configValue,
-				myType,
-				currentIndex,
-				section,
-				extractBool,
-			)
+	if section == "" {
+		section = mainSection
+	}
 
-			currentField.SetBool(boolValue)
-		case reflect.Float32, reflect.Float64:
-			fByName := myType.Field(currentIndex)
-			if !fByName.IsExported() {
-				continue
-			}
+	key := fByName.Tag.Get("key")
+	if key == "" {
+		// convert the field name to snake case
+		key = caseUtils.ToSnakeCase(fByName.Name)
+	}
 
-			floatValue := extractFieldValue(
-				configValue,
-				myType,
-				currentIndex,
-				section,
-				extractFloat64,
-			)
-			if floatValue != 0 {
-				currentField.SetFloat(floatValue)
-			}
-		case reflect.Complex64, reflect.Complex128:
-			fByName := myType.Field(currentIndex)
-			if !fByName.IsExported() {
-				continue
-			}
+	return section, key
+}
 
-			complexValue := extractFieldValue(
-				configValue,
-				myType,
-				currentIndex,
-				section,
-				extractComplex128,
-			)
-			if complexValue != 0 {
-				currentField.SetComplex(complexValue)
-			}
-		case reflect.Array, reflect.Slice:
-			fByName := myType.Field(currentIndex)
-			if !fByName.IsExported() {
-				continue
-			}
+// parseValue fills a non-struct field with the value found in the config
+// file, the environment or the `default` tag (in this order), and returns
+// true if a value was found. The field is left unchanged if there is none.
+func (p *ConfigParser) parseValue(
+	currentField reflect.Value,
+	fByName reflect.StructField,
+	section string,
+) (bool, error) {
+	if decode := getDecoder(currentField.Type()); decode != nil {
+		decodedValue, found := extractFieldValue(
+			p,
+			fByName,
+			section,
+			func(_, strValue string) (reflect.Value, error) {
+				if strings.TrimSpace(strValue) == "" {
+					return invalidReflectValue, ErrEmptyStringValue
+				}
+
+				return decode(strValue)
+			},
+		)
+		if found {
+			currentField.Set(decodedValue)
+		}
 
-			myKind := getArrayKind(fByName.Type)
-			currentSection := section
-			if currentSection == "" {
-				currentSection = fByName.Tag.Get("section")
-			}
+		return found, nil
+	}
 
-			if currentSection == "" {
-				currentSection = configValue.options.MainSectionName
-			}
+	switch currentField.Kind() {
+	case reflect.String:
+		strValue, found := extractFieldValue(
+			p,
+			fByName,
+			section,
+			extractStr,
+		)
+		if found {
+			currentField.SetString(strValue)
+		}
 
-			key := fByName.Tag.Get("key")
-			if key == "" {
-				key = caseUtils.ToSnakeCase(fByName.Name)
-			}
+		return found, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		intValue, found := extractFieldValue(
+			p,
+			fByName,
+			section,
+			getIntConverter(currentField.Type()),
+		)
+		if found {
+			currentField.SetInt(intValue)
+		}
 
-			// fType := strings.ToLower(fByName.Tag.Get("type"))
-			envKey := fByName.Tag.Get("env")
-			// isRune := fType == "rune" || fType == "[]rune"
+		return found, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		uintValue, found := extractFieldValue(
+			p,
+			fByName,
+			section,
+			getUintConverter(currentField.Type()),
+		)
+		if found {
+			currentField.SetUint(uintValue)
+		}
 
-			valueToSet, err := configValue.getArrayValueToSet(
-				currentSection, key, envKey,
-				myKind,
-			)
-			if err != nil || valueToSet.IsNil() || !valueToSet.IsValid() {
-				continue
-			}
+		return found, nil
+	case reflect.Bool:
+		boolValue, found := extractFieldValue(
+			p,
+			fByName,
+			section,
+			extractBool,
+		)
+		if found {
+			currentField.SetBool(boolValue)
+		}
 
-			currentField.Set(valueToSet)
+		return found, nil
+	case reflect.Float32, reflect.Float64:
+		floatValue, found := extractFieldValue(
+			p,
+			fByName,
+			section,
+			extractFloat64,
+		)
+		if found {
+			currentField.SetFloat(floatValue)
 		}
+
+		return found, nil
+	case reflect.Complex64, reflect.Complex128:
+		complexValue, found := extractFieldValue(
+			p,
+			fByName,
+			section,
+			extractComplex128,
+		)
+		if found {
+			currentField.SetComplex(complexValue)
+		}
+
+		return found, nil
+	case reflect.Array, reflect.Slice:
+		currentSection, key := p.getFieldLocation(fByName, section)
+
+		// fType := strings.ToLower(fByName.Tag.Get("type"))
+		envKey := fByName.Tag.Get("env")
+		// isRune := fType == "rune" || fType == "[]rune"
+
+		valueToSet, err := p.getArrayValueToSet(
+			currentSection, key, envKey,
+			fByName.Tag.Get("default"),
+			currentField.Type(),
+		)
+		if err != nil || !valueToSet.IsValid() || valueToSet.IsNil() {
+			return false, nil
+		}
+
+		currentField.Set(valueToSet)
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// parseStructPointer fills a pointer-to-struct field from its own section.
+// A nil pointer is only allocated when its section (or one of its sub-sections)
+// exists, or when any of its fields got a value from another source (such as
+// the environment); otherwise it's left nil.
+func (p *ConfigParser) parseStructPointer(
+	currentField reflect.Value,
+	fByName reflect.StructField,
+	parentSection string,
+) error {
+	nestedSection := getNestedSectionName(fByName, parentSection)
+	if !currentField.IsNil() {
+		return p.parseStruct(currentField.Elem(), nestedSection, nestedSection)
+	}
+
+	newValue := reflect.New(fByName.Type.Elem())
+	violationsCount := len(p.violations)
+	err := p.parseStruct(newValue.Elem(), nestedSection, nestedSection)
+	if err != nil {
+		return err
+	}
+
+	if p.hasSectionOrSubSection(nestedSection) || !newValue.Elem().IsZero() {
+		currentField.Set(newValue)
+	} else {
+		// the struct is discarded, so are its violations.
+		p.violations = p.violations[:violationsCount]
 	}
 
 	return nil
 }
 
+// getNestedSectionName returns the section name a nested struct field is
+// mapped to: its `section` tag (or its snake-cased name), placed under
+// parentSection using a dot if parentSection is not empty.
+func getNestedSectionName(fByName reflect.StructField, parentSection string) string {
+	name := fByName.Tag.Get("section")
+	if name == "" {
+		name = caseUtils.ToSnakeCase(fByName.Name)
+	}
+
+	if parentSection == "" {
+		return name
+	}
+
+	return parentSection + "." + name
+}
+
+// getColumn returns the column (starting at 1) of the first non-space
+// character of line.
+func getColumn(line string) int {
+	return len(line) - len(strings.TrimLeftFunc(line, unicode.IsSpace)) + 1
+}
+
+// getEnvTries returns the environment variables the value of a non-slice
+// field is looked up in, in order: the one of its `env` tag, or if it has
+// none and readEnv is true, "SECTION_KEY", "key" and "KEY".
+func getEnvTries(envTag, section, key string, readEnv bool) []string {
+	if envTag != "" {
+		// if we are given an env tag, just use that, instead of trying a few times
+		// to find the correct variable in env...
+		return []string{envTag}
+	} else if !readEnv {
+		return nil
+	}
+
+	var envTries []string
+	if section != "" {
+		envTries = append(envTries, getEnvSectionName(section)+"_"+strings.ToUpper(key))
+	}
+
+	return append(envTries, key, strings.ToUpper(key))
+}
+
+// getArrayEnvTries returns the environment variables the value of a slice
+// field is looked up in, in order: the one of its `env` tag if any, then
+// "SECTION_KEY", "key" and "KEY".
+func getArrayEnvTries(envTag, section, key string) []string {
+	var envTries []string
+	if envTag != "" {
+		envTries = append(envTries, envTag)
+	}
+
+	envTries = append(envTries, getEnvSectionName(section)+"_"+strings.ToUpper(key))
+	return append(envTries, key, strings.ToUpper(key))
+}
+
+// getEnvSectionName converts a section name to the form used in environment
+// variable names, e.g. "database.replica" becomes "DATABASE_REPLICA".
+func getEnvSectionName(section string) string {
+	return strings.ToUpper(strings.ReplaceAll(section, ".", "_"))
+}
+
 func getArrayKind(t reflect.Type) reflect.Kind {
 	myStr := t.String()
 	if !strings.HasPrefix(myStr, "[]") {
@@ -459,92 +735,69 @@ func getNoOptionError(section, option string) error {
 	return fmt.Errorf("no option '%s' in section: '%s'", option, section)
 }
 
-func parseFile(file *os.File) (*ConfigParser, error) {
-	p := NewConfigParser()
-
-	reader := bufio.NewReader(file)
-	var lineNo int
-	var curSect *Section
-
-	for {
-		l, _, err := reader.ReadLine()
-		if err != nil {
-			break
-		}
-		lineNo++
-		if len(l) == 0 {
-			continue
-		}
-		line := strings.TrimFunc(string(l), unicode.IsSpace)
+func parseBytes(value []byte, opt *ConfigParserOptions) (*ConfigParser, error) {
+	if len(value) == 0 {
+		return newLineParser(opt).finish()
+	}
 
-		// Skip comment lines and empty lines
-		if strings.HasPrefix(line, "#") || line == "" {
-			continue
-		}
+	return parseReader(bytes.NewReader(value), opt)
+}
 
-		if match := sectionHeader.FindStringSubmatch(line); len(match) > 0 {
-			section := match[1]
-			if section == defaultSectionName {
-				curSect = p.defaults
-			} else if _, present := p.config[section]; !present {
-				curSect = newSection(section)
-				p.config[section] = curSect
-			}
-		} else if match = keyValue.FindStringSubmatch(line); len(match) > 0 {
-			if curSect == nil {
-				return nil, fmt.Errorf("missing Section Header: %d %s", lineNo, line)
-			}
-			curSect.Add(strings.TrimSpace(match[1]), match[3])
-		}
+func parseString(value string, opt *ConfigParserOptions) (*ConfigParser, error) {
+	if value == "" {
+		return newLineParser(opt).finish()
 	}
-	return p, nil
-}
 
-func parseBytes(value []byte) (*ConfigParser, error) {
-	return parseString(string(value))
+	return parseReader(strings.NewReader(value), opt)
 }
 
-func parseString(value string) (*ConfigParser, error) {
-	p := NewConfigParser()
-	allLines := strings.Split(value, "\n")
-	var lineNo int
-	var curSect *Section
-
-	for _, current := range allLines {
+// parseReader parses the source read from r, with the format of opt (see
+// getFormat). Its include directives are never resolved, so that a config
+// source which is not a file can't read local files.
+func parseReader(r io.Reader, opt *ConfigParserOptions) (*ConfigParser, error) {
+	if opt != nil && opt.Includes {
+		withoutIncludes := *opt
+		withoutIncludes.Includes = false
+		opt = &withoutIncludes
+	}
 
-		lineNo++
-		if len(current) == 0 {
-			continue
-		}
+	p, err := getFormat("", opt).Parse(r, "", opt)
+	if err != nil {
+		return nil, err
+	}
 
-		line := strings.TrimSpace(current)
+	p.dotEnv, err = loadDotEnvFiles(opt)
+	if err != nil {
+		return nil, err
+	}
 
-		// Skip comment lines and empty lines
-		if strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") || line == "" {
-			continue
-		}
+	return p, nil
+}
 
-		if match := sectionHeader.FindStringSubmatch(line); len(match) > 0 {
-			section := match[1]
-			if section == defaultSectionName {
-				curSect = p.defaults
-			} else if _, present := p.config[section]; !present {
-				curSect = newSection(section)
-				p.config[section] = curSect
-			}
-		} else if match = keyValue.FindStringSubmatch(line); len(match) > 0 {
-			if curSect == nil {
-				return nil, fmt.Errorf("missing Section Header: %d %s", lineNo, line)
-			}
+func newLineParser(opt *ConfigParserOptions) *lineParser {
+	lp := &lineParser{
+		p:            NewConfigParser(),
+		options:      opt,
+		sectionLines: make(map[string]int),
+	}
 
-			curSect.Add(strings.TrimSpace(match[1]), match[3])
-		}
+	lp.p.options = opt
+	if lp.options == nil {
+		lp.options = &ConfigParserOptions{}
 	}
 
-	return p, nil
+	return lp
 }
 
-func parseToStringArray(value string) []string {
+// parseToStringArray splits value, a list of values, into its elements.
+// Quoted elements (see splitQuotedArray) are only supported if quotedValues
+// is true (see ConfigParserOptions.QuotedValues), or in the lists written by
+// joinArrayElements.
+func parseToStringArray(value string, quotedValues bool) []string {
+	if (quotedValues && strings.ContainsAny(value, `"'`)) || isJoinedArray(value) {
+		return splitQuotedArray(value)
+	}
+
 	arr := internal.SplitN(value, -1, ",", " ", "[", "]")
 
 	for i := 0; i < len(arr); i++ {
@@ -0,0 +1,115 @@
+package strongParser
+
+import (
+	"encoding"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/ALiwoto/ssg/ssg/timeUtils"
+)
+
+func init() {
+	RegisterDecoder(func(value string) (time.Duration, error) {
+		return timeUtils.ParseDuration(strings.TrimSpace(value))
+	})
+	RegisterDecoder(func(value string) (url.URL, error) {
+		u, err := url.Parse(strings.TrimSpace(value))
+		if err != nil {
+			return url.URL{}, err
+		}
+
+		return *u, nil
+	})
+}
+
+// RegisterDecoder registers fn as the decoder of the fields of type T (and of
+// *T and []T), replacing any decoder previously registered for T.
+// Registered decoders take precedence over encoding.TextUnmarshaler and the
+// built-in conversions; time.Duration (using timeUtils.ParseDuration, so
+// values such as "1d12h" and "2w" are accepted) and url.URL are registered
+// by default.
+func RegisterDecoder[T any](fn func(value string) (T, error)) {
+	myType := reflect.TypeOf((*T)(nil)).Elem()
+
+	decodersMut.Lock()
+	decoders[myType] = func(value string) (reflect.Value, error) {
+		result, err := fn(value)
+		if err != nil {
+			return invalidReflectValue, err
+		}
+
+		resultValue := reflect.New(myType).Elem()
+		resultValue.Set(reflect.ValueOf(&result).Elem())
+		return resultValue, nil
+	}
+	decodersMut.Unlock()
+}
+
+// getDecoder returns the decoder for values of type t: a registered one, or
+// one using encoding.TextUnmarshaler if *t implements it. Pointer types use
+// the decoder of their element type. It returns nil if t has no decoder.
+func getDecoder(t reflect.Type) valueDecoder {
+	decodersMut.RLock()
+	decode := decoders[t]
+	decodersMut.RUnlock()
+	if decode != nil {
+		return decode
+	}
+
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return func(value string) (reflect.Value, error) {
+			resultValue := reflect.New(t)
+			unmarshaler := resultValue.Interface().(encoding.TextUnmarshaler)
+			err := unmarshaler.UnmarshalText([]byte(strings.TrimSpace(value)))
+			if err != nil {
+				return invalidReflectValue, err
+			}
+
+			return resultValue.Elem(), nil
+		}
+	}
+
+	if t.Kind() != reflect.Ptr {
+		return nil
+	}
+
+	elemDecode := getDecoder(t.Elem())
+	if elemDecode == nil {
+		return nil
+	}
+
+	return func(value string) (reflect.Value, error) {
+		elemValue, err := elemDecode(value)
+		if err != nil {
+			return invalidReflectValue, err
+		}
+
+		resultValue := reflect.New(t.Elem())
+		resultValue.Elem().Set(elemValue)
+		return resultValue, nil
+	}
+}
+
+// parseToDecodedArray splits value into its elements, and decodes each of
+// them using decode. Elements that can't be decoded are skipped.
+func parseToDecodedArray(value string, arrayType reflect.Type, decode valueDecoder, quotedValues bool) rValue {
+	arr := parseToStringArray(value, quotedValues)
+	result := reflect.MakeSlice(arrayType, 0, len(arr))
+
+	for _, current := range arr {
+		if current == "" {
+			continue
+		}
+
+		elemValue, err := decode(current)
+		if err != nil {
+			continue
+		}
+
+		result = reflect.Append(result, elemValue)
+	}
+
+	return result
+}
@@ -0,0 +1,146 @@
+package strongParser
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// lookupEnv returns the value of the environment variable name, prefixed
+// with the EnvPrefix of opt; the variables of the process take precedence
+// over the ones of dotEnv (see ConfigParserOptions.DotEnvFiles).
+func lookupEnv(opt *ConfigParserOptions, dotEnv map[string]string, name string) string {
+	if opt != nil {
+		name = opt.EnvPrefix + name
+	}
+
+	if value, ok := os.LookupEnv(name); ok {
+		return value
+	}
+
+	return dotEnv[name]
+}
+
+// loadDotEnvFiles loads the variables of the DotEnvFiles of opt. The result
+// is never nil, so it can be told apart from variables that are not loaded.
+func loadDotEnvFiles(opt *ConfigParserOptions) (map[string]string, error) {
+	env := make(map[string]string)
+	if opt == nil {
+		return env, nil
+	}
+
+	var parseErrs ParseErrors
+	for _, path := range opt.DotEnvFiles {
+		var fileErrs ParseErrors
+		err := parseDotEnvFile(path, env)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if errors.As(err, &fileErrs) {
+			parseErrs = append(parseErrs, fileErrs...)
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(parseErrs) != 0 {
+		return nil, parseErrs
+	}
+
+	return env, nil
+}
+
+// parseDotEnvFile adds the variables of the named .env file to env. Lines
+// are in the form of "NAME=value" (optionally after "export "); values may be
+// double quoted (with escape sequences) or single quoted (taken literally),
+// and unquoted values may be followed by a comment starting with " #".
+func parseDotEnvFile(path string, env map[string]string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return readDotEnv(file, path, func(name, value string, _ int) {
+		env[name] = value
+	})
+}
+
+// readDotEnv reads the variables of a .env source from r, and passes them to
+// add in order, with the number of their line; filename is used in the
+// returned errors.
+func readDotEnv(r io.Reader, filename string, add func(name, value string, lineNo int)) error {
+	var parseErrs ParseErrors
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, value, ok := parseDotEnvLine(line)
+		if !ok {
+			parseErrs = append(parseErrs, &ParseError{
+				File:    filename,
+				Line:    lineNo,
+				Column:  getColumn(scanner.Text()),
+				Snippet: line,
+				Kind:    ParseErrorMalformedLine,
+			})
+			continue
+		}
+
+		add(name, value, lineNo)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	if len(parseErrs) != 0 {
+		return parseErrs
+	}
+
+	return nil
+}
+
+// parseDotEnvLine parses a trimmed line of a .env file; it returns false if
+// the line is malformed.
+func parseDotEnvLine(line string) (string, string, bool) {
+	line = strings.TrimPrefix(line, "export ")
+	name, value, ok := strings.Cut(line, "=")
+	name = strings.TrimSpace(name)
+	if !ok || !dotEnvName.MatchString(name) {
+		return "", "", false
+	}
+
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return name, "", true
+	}
+
+	switch value[0] {
+	case '"':
+		end := indexUnescaped(value[1:], `"`)
+		if end == -1 {
+			return "", "", false
+		}
+
+		return name, unescapeValue(value[1 : end+1]), true
+	case '\'':
+		end := strings.IndexByte(value[1:], '\'')
+		if end == -1 {
+			return "", "", false
+		}
+
+		return name, value[1 : end+1], true
+	}
+
+	if index := strings.Index(value, " #"); index != -1 {
+		value = strings.TrimSpace(value[:index])
+	}
+
+	return name, value, true
+}
@@ -1,76 +1,82 @@
 package strongParser
 
 import (
-	"os"
+	"errors"
+	"fmt"
 	"reflect"
 	"strconv"
 	"strings"
 
-	"github.com/ALiwoto/ssg/ssg/caseUtils"
 	"github.com/ALiwoto/ssg/ssg/commonUtils"
 )
 
-// extractFieldValue
-func extractFieldValue[T comparable](
+// extractFieldValue looks up the value of a field in the config file, then
+// in the environment, and finally in its `default` tag. The second return
+// value is false if none of them has a value that converter accepts, so a
+// missing value can be told apart from an explicit zero value.
+func extractFieldValue[T any](
 	parser *ConfigParser,
-	myType reflect.Type,
-	currentIndex int, section string,
-	converter fieldValueConverter[T]) T {
+	fByName reflect.StructField,
+	section string,
+	converter fieldValueConverter[T]) (T, bool) {
 
 	var resultValue T
-	fByName := myType.Field(currentIndex)
-	if section == "" {
-		section = fByName.Tag.Get("section")
-	}
-
-	if section == "" && parser.options.MainSectionName != "" {
-		section = parser.options.MainSectionName
-	}
+	section, key := parser.getFieldLocation(fByName, section)
 
-	key := fByName.Tag.Get("key")
-	if key == "" {
-		// convert the field name to snake case
-		key = caseUtils.ToSnakeCase(fByName.Name)
-	}
+	parser.addKnownKey(section, key)
 
 	fType := strings.ToLower(fByName.Tag.Get("type"))
 	theValue, err := parser.Get(section, key)
-	if err == nil {
+	if parser.reportValueError(section, key, err) {
+		return resultValue, false
+	} else if err == nil {
 		// first try: from config file.
 		resultValue, err = converter(fType, theValue)
 		if err == nil {
-			return resultValue
-		}
-	}
-
-	envTag := fByName.Tag.Get("env")
-	var envTries []string
-	if envTag == "" && parser.options.ReadEnv {
-		// if there is no env tag and we are told to allow
-		// reading values from env, try to read it from env.
-		if section != "" {
-			envTries = append(envTries, strings.ToUpper(section)+"_"+strings.ToUpper(key))
+			parser.addValueSource(section, key, SourceFile, "", theValue)
+			return resultValue, true
+		} else if isTypeMismatch(parser, err) {
+			parser.addTypeMismatch(section, key, maskValue(theValue, isSecretField(fByName)), err)
+			return resultValue, false
 		}
-		envTries = append(envTries, key)
-		envTries = append(envTries, strings.ToUpper(key))
-	} else {
-		// if we are given an env tag, just use that, instead of trying a few times
-		// to find the correct variable in env...
-		envTries = append(envTries, envTag)
 	}
 
+	envTries := getEnvTries(fByName.Tag.Get("env"), section, key, parser.options.ReadEnv)
 	for _, envTry := range envTries {
-		envValue := os.Getenv(envTry)
-		if envValue != "" {
+		envValue, err := parser.getEnv(section, key, envTry)
+		if parser.reportValueError(section, key, err) {
+			return resultValue, false
+		} else if envValue != "" {
 			resultValue, err = converter(fType, envValue)
 			if err == nil {
-				return resultValue
+				parser.addValueSource(section, key, SourceEnv, envTry, envValue)
+				return resultValue, true
+			} else if isTypeMismatch(parser, err) {
+				parser.addEnvTypeMismatch(section, key, envTry, maskValue(envValue, isSecretField(fByName)), err)
+				return resultValue, false
 			}
 		}
 	}
 
-	resultValue, _ = converter(fType, fByName.Tag.Get("default"))
-	return resultValue
+	defaultValue, hasDefault := fByName.Tag.Lookup("default")
+	if !hasDefault {
+		return resultValue, false
+	}
+
+	resultValue, err = converter(fType, defaultValue)
+	if err != nil {
+		var zeroValue T
+		return zeroValue, false
+	}
+
+	parser.addValueSource(section, key, SourceDefault, "", defaultValue)
+	return resultValue, true
+}
+
+// isTypeMismatch returns true if err, returned by a converter, has to be
+// reported by the Strict option; empty values are treated as missing ones.
+func isTypeMismatch(parser *ConfigParser, err error) bool {
+	return parser.options.Strict && !errors.Is(err, ErrEmptyStringValue)
 }
 
 func extractStr(fType, s string) (string, error) {
@@ -105,6 +111,32 @@ func extractUInt64(_, strValue string) (uint64, error) {
 	return strconv.ParseUint(strValue, 10, 64)
 }
 
+// getIntConverter returns a converter like extractInt64, which rejects the
+// values that overflow t.
+func getIntConverter(t reflect.Type) fieldValueConverter[int64] {
+	return func(fType, strValue string) (int64, error) {
+		intValue, err := extractInt64(fType, strValue)
+		if err == nil && reflect.Zero(t).OverflowInt(intValue) {
+			return 0, fmt.Errorf("value %d overflows %s", intValue, t)
+		}
+
+		return intValue, err
+	}
+}
+
+// getUintConverter returns a converter like extractUInt64, which rejects the
+// values that overflow t.
+func getUintConverter(t reflect.Type) fieldValueConverter[uint64] {
+	return func(fType, strValue string) (uint64, error) {
+		uintValue, err := extractUInt64(fType, strValue)
+		if err == nil && reflect.Zero(t).OverflowUint(uintValue) {
+			return 0, fmt.Errorf("value %d overflows %s", uintValue, t)
+		}
+
+		return uintValue, err
+	}
+}
+
 func extractBool(fType, strValue string) (bool, error) {
 	strValue = strings.TrimSpace(strings.ToLower(strValue))
 	strValue = strings.Trim(strValue, "\"")
@@ -122,3 +154,76 @@ func extractFloat64(fType, strValue string) (float64, error) {
 func extractComplex128(fType, strValue string) (complex128, error) {
 	return strconv.ParseComplex(strValue, 128)
 }
+
+// convertString converts value to a value of type t, using the same
+// conversions as struct fields (decoders, scalar kinds, slices and pointers
+// to them).
+func convertString(t reflect.Type, fType, value string) (reflect.Value, error) {
+	if decode := getDecoder(t); decode != nil {
+		if strings.TrimSpace(value) == "" {
+			return invalidReflectValue, ErrEmptyStringValue
+		}
+
+		return decode(value)
+	}
+
+	result := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.String:
+		strValue, err := extractStr(fType, value)
+		if err != nil {
+			return invalidReflectValue, err
+		}
+
+		result.SetString(strValue)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		intValue, err := getIntConverter(t)(fType, value)
+		if err != nil {
+			return invalidReflectValue, err
+		}
+
+		result.SetInt(intValue)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		uintValue, err := getUintConverter(t)(fType, value)
+		if err != nil {
+			return invalidReflectValue, err
+		}
+
+		result.SetUint(uintValue)
+	case reflect.Bool:
+		boolValue, err := extractBool(fType, value)
+		if err != nil {
+			return invalidReflectValue, err
+		}
+
+		result.SetBool(boolValue)
+	case reflect.Float32, reflect.Float64:
+		floatValue, err := extractFloat64(fType, value)
+		if err != nil {
+			return invalidReflectValue, err
+		}
+
+		result.SetFloat(floatValue)
+	case reflect.Complex64, reflect.Complex128:
+		complexValue, err := extractComplex128(fType, value)
+		if err != nil {
+			return invalidReflectValue, err
+		}
+
+		result.SetComplex(complexValue)
+	case reflect.Slice:
+		return parseArrayValue(value, t, false)
+	case reflect.Ptr:
+		elemValue, err := convertString(t.Elem(), fType, value)
+		if err != nil {
+			return invalidReflectValue, err
+		}
+
+		result = reflect.New(t.Elem())
+		result.Elem().Set(elemValue)
+	default:
+		return invalidReflectValue, fmt.Errorf("unsupported kind: %s", t.Kind())
+	}
+
+	return result, nil
+}
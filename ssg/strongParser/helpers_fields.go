@@ -0,0 +1,141 @@
+package strongParser
+
+import (
+	"reflect"
+	"strings"
+)
+
+// walkFields calls visit for each field of the struct type t that is filled
+// from the config file, in order, following the same rules as parseStruct:
+// embedded structs are flattened, nested structs (and pointers to them) are
+// walked into, and their fields are
+// visited with the sections they are mapped to; the fields of the elements
+// of maps and slices of structs are not. section and parentSection
+// have the same meaning as in parseStruct, and path is the path of t in the
+// root struct ("" for the root struct itself).
+func walkFields(
+	t reflect.Type,
+	section, parentSection, path, mainSection string,
+	visit func(info *fieldInfo),
+) {
+	for i := 0; i < t.NumField(); i++ {
+		fByName := t.Field(i)
+		if isEmbeddedStruct(fByName) {
+			embeddedType := fByName.Type
+			if embeddedType.Kind() == reflect.Ptr {
+				embeddedType = embeddedType.Elem()
+			}
+
+			// promoted fields keep the path of the struct that embeds them.
+			walkFields(embeddedType, section, parentSection, path, mainSection, visit)
+			continue
+		}
+
+		if !fByName.IsExported() {
+			continue
+		}
+
+		fieldPath := fByName.Name
+		if path != "" {
+			fieldPath = path + "." + fByName.Name
+		}
+
+		fType := fByName.Type
+		if fType.Kind() == reflect.Ptr {
+			if fType.Elem().Kind() == reflect.Ptr {
+				// not supported by parseField either.
+				continue
+			}
+
+			fType = fType.Elem()
+		}
+
+		switch {
+		case isStructType(fType):
+			nestedSection := getNestedSectionName(fByName, parentSection)
+			walkFields(fType, nestedSection, nestedSection, fieldPath, mainSection, visit)
+		case fType.Kind() == reflect.Map:
+			if fType.Key().Kind() != reflect.String {
+				continue
+			}
+
+			info := &fieldInfo{
+				kind:      fieldMap,
+				field:     fByName,
+				path:      fieldPath,
+				valueType: fType.Elem(),
+				section:   getNestedSectionName(fByName, parentSection),
+			}
+			if isStructType(info.valueType) {
+				info.kind = fieldStructMap
+			}
+
+			visit(info)
+		case fType.Kind() == reflect.Slice && isStructType(fType.Elem()):
+			visit(&fieldInfo{
+				kind:      fieldStructSlice,
+				field:     fByName,
+				path:      fieldPath,
+				valueType: fType.Elem(),
+				section:   getNestedSectionName(fByName, parentSection),
+			})
+		default:
+			fieldSection, key := getFieldLocation(fByName, section, mainSection)
+			visit(&fieldInfo{
+				kind:      fieldValue,
+				field:     fByName,
+				path:      fieldPath,
+				valueType: fType,
+				section:   fieldSection,
+				key:       key,
+			})
+		}
+	}
+}
+
+// getStructType returns the struct type of v, which may be a struct, or a
+// (possibly nil) pointer to one; it returns nil otherwise.
+func getStructType(v any) reflect.Type {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	return t
+}
+
+// getTypeName returns the name of the type of a field, as shown in generated
+// templates: its `type` tag, or the Go type.
+func getTypeName(info *fieldInfo) string {
+	if fType := strings.ToLower(info.field.Tag.Get("type")); fType != "" {
+		return fType
+	}
+
+	return info.valueType.String()
+}
+
+// getFieldEnvNames returns the environment variables the value of a field
+// is looked up in by ParseConfig (with the default options), in order.
+func getFieldEnvNames(info *fieldInfo) []string {
+	envTag := info.field.Tag.Get("env")
+	var envTries []string
+	kind := info.valueType.Kind()
+	if (kind == reflect.Slice || kind == reflect.Array) && getDecoder(info.valueType) == nil {
+		envTries = getArrayEnvTries(envTag, info.section, info.key)
+	} else {
+		envTries = getEnvTries(envTag, info.section, info.key, true)
+	}
+
+	names := make([]string, 0, len(envTries))
+	for _, name := range envTries {
+		if !containsString(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,151 @@
+package strongParser
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// ParseConfigWithFlags parses the named file into value like ParseConfig,
+// with command-line flags taking precedence over the file and the
+// environment. args are the arguments without the program name, usually
+// os.Args[1:].
+//
+// There is a flag for each field that is read from a single option, named
+// "section.key" (such as --main.port or -database.url); its value is checked
+// against the type of the field, and boolean flags may be given without a
+// value. Maps can't be set from flags. Arguments after the flags are ignored.
+//
+// The usage, which lists the flags with their `desc`, `default`, validation
+// and `env` tags, is printed to os.Stderr when a flag is invalid, or when -h
+// or --help is given; flag.ErrHelp is returned in the latter case.
+func ParseConfigWithFlags(value any, filename string, args []string) error {
+	return ParseConfigWithFlagsAndOption(value, filename, args, nil)
+}
+
+// ParseConfigWithFlagsAndOption is like ParseConfigWithFlags, using opt as in
+// ParseConfigWithOption.
+func ParseConfigWithFlagsAndOption(value any, filename string, args []string, opt *ConfigParserOptions) error {
+	_, err := parseConfigWithFlags(value, filename, args, opt, false)
+	return err
+}
+
+// ParseConfigWithFlagsAndReport is like ParseConfigWithFlagsAndOption, and
+// returns the sources of the values like ParseConfigWithReport.
+func ParseConfigWithFlagsAndReport(
+	value any,
+	filename string,
+	args []string,
+	opt *ConfigParserOptions,
+) (ConfigReport, error) {
+	return parseConfigWithFlags(value, filename, args, opt, true)
+}
+
+func parseConfigWithFlags(
+	value any,
+	filename string,
+	args []string,
+	opt *ConfigParserOptions,
+	withReport bool,
+) (ConfigReport, error) {
+	t := getStructType(value)
+	if t == nil || reflect.ValueOf(value).Kind() != reflect.Ptr {
+		return nil, &InvalidParseError{reflect.TypeOf(value)}
+	}
+
+	mainSection := DefaultMainSection
+	if opt != nil && opt.MainSectionName != "" {
+		mainSection = opt.MainSectionName
+	}
+
+	flagSet, flags := newConfigFlagSet(t, mainSection, os.Stderr)
+	if err := flagSet.Parse(args); err != nil {
+		return nil, err
+	}
+
+	p, err := ParseWithOption(filename, opt)
+	if err != nil {
+		p, err = getEnvOnlyParser(err, opt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	p.options = opt
+	for _, current := range flags {
+		if current.isSet {
+			// the values of the file are the first ones to be looked up.
+			section := p.getOrAddSection(current.info.section)
+			section.addValue(current.info.key, current.value)
+			section.setSource(current.info.key, &ValueSource{
+				Kind: SourceFlag,
+				Flag: "--" + current.info.section + "." + current.info.key,
+			})
+		}
+	}
+
+	if withReport {
+		return parseConfigReport(value, p)
+	}
+
+	return nil, parseFinalConfig(value, "", p)
+}
+
+// newConfigFlagSet returns a flag set with the flags of the fields of the
+// config struct type t, and these flags; its usage is written to output.
+func newConfigFlagSet(t reflect.Type, mainSection string, output io.Writer) (*flag.FlagSet, []*fieldFlag) {
+	flagSet := flag.NewFlagSet(filepath.Base(os.Args[0]), flag.ContinueOnError)
+	flagSet.SetOutput(output)
+
+	var flags []*fieldFlag
+	walkFields(t, "", "", "", mainSection, func(info *fieldInfo) {
+		name := info.section + "." + info.key
+		if info.kind != fieldValue || flagSet.Lookup(name) != nil {
+			// a field mapped to the same option as another one shares its flag.
+			return
+		}
+
+		current := &fieldFlag{info: info}
+		flags = append(flags, current)
+		flagSet.Var(current, name, strings.TrimSpace(info.field.Tag.Get("desc")))
+	})
+
+	flagSet.Usage = func() {
+		fmt.Fprintf(output, "Usage of %s:\n", flagSet.Name())
+		writeFlagUsage(output, flags)
+	}
+
+	return flagSet, flags
+}
+
+// writeFlagUsage writes the description of flags to w, in the same format
+// as the flag package.
+func writeFlagUsage(w io.Writer, flags []*fieldFlag) {
+	for _, current := range flags {
+		info := current.info
+		line := "  --" + info.section + "." + info.key
+		if !current.IsBoolFlag() {
+			line += " " + getTypeName(info)
+		}
+
+		var details []string
+		if defaultValue, ok := info.field.Tag.Lookup("default"); ok {
+			details = append(details, "default: "+defaultValue)
+		}
+
+		details = append(details, getConstraints(info.field.Tag)...)
+		details = append(details, "env: "+strings.Join(getFieldEnvNames(info), ", "))
+
+		usage := strings.TrimSpace(info.field.Tag.Get("desc"))
+		if usage != "" {
+			usage += " "
+		}
+
+		usage += "(" + strings.Join(details, "; ") + ")"
+		fmt.Fprintf(w, "%s\n    \t%s\n", line, strings.ReplaceAll(usage, "\n", "\n    \t"))
+	}
+}
@@ -0,0 +1,196 @@
+package strongParser
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// getFormat returns the format of the named file (see
+// ConfigParserOptions.Format); filename is empty for strings.
+func getFormat(filename string, opt *ConfigParserOptions) Format {
+	if opt != nil && opt.Format != nil {
+		return opt.Format
+	}
+
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json":
+		return JSONFormat
+	case ".env":
+		return DotEnvFormat
+	}
+
+	return INIFormat
+}
+
+// getMainSectionName returns the name of the main section of opt.
+func getMainSectionName(opt *ConfigParserOptions) string {
+	if opt == nil || opt.MainSectionName == "" {
+		return DefaultMainSection
+	}
+
+	return opt.MainSectionName
+}
+
+// readJSONSection reads the members of a JSON object, whose opening brace
+// has already been read, and passes them to add with their section; the
+// closing brace is read too. Nested objects are read into the sections named
+// "section.key", and passed to add with an empty key first, so that empty
+// objects are sections too; the objects of an array are read into the
+// sections named "section.key.1", "section.key.2" and so on (see
+// getSliceSectionName). An empty section is the top-level object, whose
+// other members are read into mainSection.
+func readJSONSection(
+	decoder *json.Decoder,
+	section, mainSection string,
+	add func(section, key, value string),
+) error {
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+
+		// the tokens of object keys are always strings.
+		key := token.(string)
+		target := section
+		if target == "" {
+			target = mainSection
+		}
+
+		token, err = decoder.Token()
+		if err != nil {
+			return err
+		}
+
+		nestedSection := key
+		if section != "" {
+			nestedSection = section + "." + key
+		}
+
+		switch value := token.(type) {
+		case nil:
+			continue
+		case json.Delim:
+			if value == '[' {
+				elements, hasObjects, err := readJSONArray(decoder, nestedSection, mainSection, add)
+				if err != nil {
+					return err
+				}
+
+				if !hasObjects {
+					add(target, key, joinArrayElements(elements))
+				}
+
+				continue
+			}
+
+			add(nestedSection, "", "")
+			err = readJSONSection(decoder, nestedSection, mainSection, add)
+			if err != nil {
+				return err
+			}
+		default:
+			add(target, key, formatJSONValue(value))
+		}
+	}
+
+	_, err := decoder.Token()
+	return err
+}
+
+// readJSONArray reads the elements of a JSON array, whose opening bracket
+// has already been read; the closing bracket is read too. The elements must
+// be either scalar values, which are returned, or objects, which are read
+// into the sections of pattern like readJSONSection does; hasObjects is true
+// in the latter case.
+func readJSONArray(
+	decoder *json.Decoder,
+	pattern, mainSection string,
+	add func(section, key, value string),
+) (elements []string, hasObjects bool, err error) {
+	for index := 0; decoder.More(); {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, false, err
+		}
+
+		switch token {
+		case nil:
+			continue
+		case json.Delim('{'):
+			if len(elements) != 0 {
+				return nil, false, errors.New("arrays may not mix objects and other values")
+			}
+
+			hasObjects = true
+			elementSection := getSliceSectionName(pattern, index)
+			index++
+
+			add(elementSection, "", "")
+			err = readJSONSection(decoder, elementSection, mainSection, add)
+			if err != nil {
+				return nil, false, err
+			}
+
+			continue
+		case json.Delim('['):
+			return nil, false, errors.New("arrays may not contain arrays")
+		}
+
+		if hasObjects {
+			return nil, false, errors.New("arrays may not mix objects and other values")
+		}
+
+		elements = append(elements, formatJSONValue(token))
+	}
+
+	_, err = decoder.Token()
+	return elements, hasObjects, err
+}
+
+// formatJSONValue converts a scalar JSON token to its string form.
+func formatJSONValue(token json.Token) string {
+	switch value := token.(type) {
+	case string:
+		return value
+	case bool:
+		return strconv.FormatBool(value)
+	case json.Number:
+		return value.String()
+	}
+
+	return ""
+}
+
+// getJSONParseError converts an error of the JSON decoder to ParseErrors;
+// offset is the position in data right after the last token read.
+func getJSONParseError(filename string, data []byte, offset int64, err error) error {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		offset = syntaxErr.Offset
+	} else if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+
+	// the offset is right after the invalid character.
+	offset = max(0, min(offset-1, int64(len(data))))
+	lineStart := bytes.LastIndexByte(data[:offset], '\n') + 1
+	lineEnd := bytes.IndexByte(data[lineStart:], '\n')
+	if lineEnd == -1 {
+		lineEnd = len(data) - lineStart
+	}
+
+	return ParseErrors{&ParseError{
+		File:    filename,
+		Line:    bytes.Count(data[:offset], []byte{'\n'}) + 1,
+		Column:  int(offset) - lineStart + 1,
+		Snippet: strings.TrimSpace(string(data[lineStart : lineStart+lineEnd])),
+		Kind:    ParseErrorInvalidDocument,
+		Err:     err,
+	}}
+}
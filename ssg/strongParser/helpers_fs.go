@@ -0,0 +1,71 @@
+package strongParser
+
+import (
+	"io"
+	"io/fs"
+)
+
+// ParseReader parses the config source read from r into a ConfigParser
+// value. INI and .env sources are processed line by line, without reading
+// all of r first.
+func ParseReader(r io.Reader) (*ConfigParser, error) {
+	return parseReader(r, nil)
+}
+
+// ParseReaderWithOption is like ParseReader, using opt as in
+// ParseWithOption; the source is parsed with opt.Format, or as INI.
+// Include directives are not resolved, even with opt.Includes.
+func ParseReaderWithOption(r io.Reader, opt *ConfigParserOptions) (*ConfigParser, error) {
+	return parseReader(r, opt)
+}
+
+// ParseFS parses the named file of fsys (such as an embed.FS) into a
+// ConfigParser value. The files it includes and its profile overlay are
+// loaded from fsys too.
+func ParseFS(fsys fs.FS, name string) (*ConfigParser, error) {
+	return ParseFSWithOption(fsys, name, nil)
+}
+
+// ParseFSWithOption is like ParseFS, using opt as in ParseWithOption.
+func ParseFSWithOption(fsys fs.FS, name string, opt *ConfigParserOptions) (*ConfigParser, error) {
+	return parseLayers(fsys, []string{name}, opt)
+}
+
+// ParseReaderConfig parses the config source read from r into value; see
+// ParseReader.
+func ParseReaderConfig(value any, r io.Reader) error {
+	return ParseReaderConfigWithOption(value, r, nil)
+}
+
+// ParseReaderConfigWithOption is like ParseReaderConfig, using opt as in
+// ParseConfigWithOption.
+func ParseReaderConfigWithOption(value any, r io.Reader, opt *ConfigParserOptions) error {
+	p, err := parseReader(r, opt)
+	if err != nil {
+		return err
+	}
+
+	p.options = opt
+	return parseFinalConfig(value, "", p)
+}
+
+// ParseConfigFS parses the named file of fsys into value like ParseConfig;
+// see ParseFS.
+func ParseConfigFS(value any, fsys fs.FS, name string) error {
+	return ParseConfigFSWithOption(value, fsys, name, nil)
+}
+
+// ParseConfigFSWithOption is like ParseConfigFS, using opt as in
+// ParseConfigWithOption.
+func ParseConfigFSWithOption(value any, fsys fs.FS, name string, opt *ConfigParserOptions) error {
+	p, err := ParseFSWithOption(fsys, name, opt)
+	if err != nil {
+		p, err = getEnvOnlyParser(err, opt)
+		if err != nil {
+			return err
+		}
+	}
+
+	p.options = opt
+	return parseFinalConfig(value, "", p)
+}
@@ -0,0 +1,105 @@
+package strongParser
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// ParseLayers parses the named files and merges them in order: the values of
+// each file override the ones of the files before it. The profile overlay of
+// each file (see ConfigParserOptions.Profile) is loaded right after it.
+//
+// The returned ConfigParser keeps the layout of the last file; options that
+// come from the other files are only written back by WriteTo and SaveFile if
+// they are changed.
+func ParseLayers(paths ...string) (*ConfigParser, error) {
+	return parseLayers(nil, paths, nil)
+}
+
+// ParseLayersWithOption is like ParseLayers, using opt as in ParseWithOption.
+func ParseLayersWithOption(opt *ConfigParserOptions, paths ...string) (*ConfigParser, error) {
+	return parseLayers(nil, paths, opt)
+}
+
+// parseLayers parses the named files of fsys (or of the OS file system, if
+// it's nil) and merges them; see ParseLayers.
+func parseLayers(fsys fs.FS, paths []string, opt *ConfigParserOptions) (*ConfigParser, error) {
+	loader, err := newIncludeLoader(opt)
+	if err != nil {
+		return nil, err
+	}
+
+	loader.fsys = fsys
+
+	var layers []string
+	profile := loader.getProfile()
+	for _, path := range paths {
+		layers = append(layers, path)
+		if profile == "" {
+			continue
+		}
+
+		if overlay := getProfilePath(path, profile); loader.fileExists(overlay) {
+			layers = append(layers, overlay)
+		}
+	}
+
+	var base, top *ConfigParser
+	for _, path := range layers {
+		if _, err := loader.stat(path); errors.Is(err, fs.ErrNotExist) && loader.options.Optional {
+			// an optional file doesn't have to exist (its includes do).
+			continue
+		}
+
+		layer, err := loader.load(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if top != nil {
+			if base == nil {
+				base = NewConfigParser()
+			}
+
+			base.override(top)
+		}
+
+		top = layer
+	}
+
+	if top == nil {
+		return parseString("", opt)
+	}
+
+	if base != nil {
+		top.inherit(base)
+	}
+
+	top.files = loader.files
+	return top, nil
+}
+
+func newIncludeLoader(opt *ConfigParserOptions) (*includeLoader, error) {
+	if opt == nil {
+		opt = &ConfigParserOptions{}
+	}
+
+	dotEnv, err := loadDotEnvFiles(opt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &includeLoader{
+		options: opt,
+		dotEnv:  dotEnv,
+	}, nil
+}
+
+// getProfilePath returns the path of the profile overlay of path, e.g.
+// "config.production.ini" for "config.ini" and "production".
+func getProfilePath(path, profile string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + profile + ext
+}
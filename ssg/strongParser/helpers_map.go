@@ -0,0 +1,116 @@
+package strongParser
+
+import (
+	"reflect"
+	"strings"
+)
+
+// parseMap fills a map field whose keys are strings, and returns true if a
+// value was found for it:
+//   - map[string]T, where T is a scalar or a type with a decoder, gets every
+//     option of the section named by its `section` tag (or its snake-cased
+//     name), except for the ones of the DEFAULT section;
+//   - map[string]SubStruct (or map[string]*SubStruct) gets a struct for each
+//     section that has the same name plus a dot as prefix; for example
+//     [bot.alpha] and [bot.beta] are stored as "alpha" and "beta" for a field
+//     mapped to "bot".
+//
+// Existing entries of a non-nil map are kept, unless a section overrides them.
+func (p *ConfigParser) parseMap(
+	currentField reflect.Value,
+	fByName reflect.StructField,
+	parentSection string,
+) (bool, error) {
+	mapType := currentField.Type()
+	if mapType.Key().Kind() != reflect.String {
+		return false, nil
+	}
+
+	mapSection := getNestedSectionName(fByName, parentSection)
+	if isStructType(mapType.Elem()) {
+		return p.parseStructMap(currentField, mapSection)
+	}
+
+	p.addKnownSection(mapSection)
+	section, present := p.config[mapSection]
+	if !present {
+		return false, nil
+	}
+
+	fType := strings.ToLower(fByName.Tag.Get("type"))
+	if currentField.IsNil() {
+		currentField.Set(reflect.MakeMap(mapType))
+	}
+
+	for _, key := range section.OrderedOptions() {
+		strValue, err := p.Get(mapSection, key)
+		if p.reportValueError(mapSection, key, err) || err != nil {
+			continue
+		}
+
+		value, err := convertString(mapType.Elem(), fType, strValue)
+		if err != nil {
+			p.addTypeMismatch(mapSection, key, maskValue(strValue, isSecretField(fByName)), err)
+			continue
+		}
+
+		currentField.SetMapIndex(reflect.ValueOf(key).Convert(mapType.Key()), value)
+	}
+
+	return true, nil
+}
+
+// parseStructMap fills a map[string]SubStruct (or map[string]*SubStruct)
+// field from the sections named "prefix.name".
+func (p *ConfigParser) parseStructMap(currentField reflect.Value, prefix string) (bool, error) {
+	mapType := currentField.Type()
+	elemType := mapType.Elem()
+	found := false
+
+	for _, sectionName := range p.sectionOrder {
+		name, ok := strings.CutPrefix(sectionName, prefix+".")
+		if !ok || name == "" || strings.Contains(name, ".") {
+			// deeper sections belong to the nested structs of the elements.
+			continue
+		}
+
+		elemValue := reflect.New(elemType).Elem()
+		structValue := elemValue
+		if elemType.Kind() == reflect.Ptr {
+			elemValue = reflect.New(elemType.Elem())
+			structValue = elemValue.Elem()
+		}
+
+		err := p.parseStruct(structValue, sectionName, sectionName)
+		if err != nil {
+			return found, err
+		}
+
+		if validS, ok := structValue.Addr().Interface().(SectionValue); ok {
+			validS.SetSectionName(sectionName)
+		}
+
+		if currentField.IsNil() {
+			currentField.Set(reflect.MakeMap(mapType))
+		}
+
+		currentField.SetMapIndex(reflect.ValueOf(name).Convert(mapType.Key()), elemValue)
+		found = true
+	}
+
+	return found, nil
+}
+
+// isStructType returns true if values of type t (or of the type t points to)
+// are parsed from their own sections, rather than from a single value.
+func isStructType(t reflect.Type) bool {
+	if getDecoder(t) != nil {
+		return false
+	}
+
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t.Kind() == reflect.Struct && getDecoder(t) == nil
+}
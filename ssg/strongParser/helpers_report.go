@@ -0,0 +1,118 @@
+package strongParser
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ParseConfigWithReport parses the named file into value like ParseConfig,
+// and returns where the value of each field came from: the file (and the
+// line) it was found on, the environment variable it was read from, or the
+// `default` tag of the field. Fields that got no value are not in the report.
+//
+// The report is returned along with the ValidationErrors of the config, if
+// any; see ConfigReport.Explain to print it.
+func ParseConfigWithReport(value any, filename string) (ConfigReport, error) {
+	return ParseConfigWithReportAndOption(value, filename, nil)
+}
+
+// ParseConfigWithReportAndOption is like ParseConfigWithReport, using opt as
+// in ParseConfigWithOption.
+func ParseConfigWithReportAndOption(value any, filename string, opt *ConfigParserOptions) (ConfigReport, error) {
+	p, err := ParseWithOption(filename, opt)
+	if err != nil {
+		p, err = getEnvOnlyParser(err, opt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	p.options = opt
+	return parseConfigReport(value, p)
+}
+
+// parseConfigReport parses p into value like parseFinalConfig, and returns
+// the sources of its values.
+func parseConfigReport(value any, p *ConfigParser) (ConfigReport, error) {
+	p.valueSources = make(map[string]map[string]*ValueSource)
+	defer func() {
+		p.valueSources = nil
+	}()
+
+	err := parseFinalConfig(value, "", p)
+	if err != nil && !errors.As(err, new(ValidationErrors)) {
+		return nil, err
+	}
+
+	report := make(ConfigReport)
+	p.addReportFields(report, getStructType(value), "", "", "")
+	return report, err
+}
+
+// addReportFields adds the sources of the values of the fields of the struct
+// type t to report. section and parentSection have the same meaning as in
+// parseStruct, and path is the path of t in the root struct.
+func (p *ConfigParser) addReportFields(report ConfigReport, t reflect.Type, section, parentSection, path string) {
+	walkFields(t, section, parentSection, path, p.options.MainSectionName, func(info *fieldInfo) {
+		isSecret := isSecretField(info.field)
+		switch info.kind {
+		case fieldValue:
+			if source := p.valueSources[info.section][strings.ToLower(info.key)]; source != nil {
+				report[info.path] = source
+				source.secret = isSecret
+			}
+		case fieldMap:
+			mapSection := p.config[info.section]
+			if mapSection == nil {
+				return
+			}
+
+			for _, key := range mapSection.OrderedOptions() {
+				value, err := p.Get(info.section, key)
+				if err != nil {
+					continue
+				}
+
+				source := getSourceCopy(mapSection.getSource(key), SourceFile)
+				source.Value = value
+				source.secret = isSecret
+				report[info.path+"["+key+"]"] = source
+			}
+		case fieldStructMap:
+			elemType := info.valueType
+			if elemType.Kind() == reflect.Ptr {
+				elemType = elemType.Elem()
+			}
+
+			for _, sectionName := range p.sectionOrder {
+				name, ok := strings.CutPrefix(sectionName, info.section+".")
+				if ok && name != "" && !strings.Contains(name, ".") {
+					p.addReportFields(report, elemType, sectionName, sectionName, info.path+"["+name+"]")
+				}
+			}
+		case fieldStructSlice:
+			elemType := info.valueType
+			if elemType.Kind() == reflect.Ptr {
+				elemType = elemType.Elem()
+			}
+
+			for i, sectionName := range p.getSliceSections(info.section) {
+				p.addReportFields(report, elemType, sectionName, sectionName,
+					info.path+"["+strconv.Itoa(i)+"]")
+			}
+		}
+	})
+}
+
+// getSourceCopy returns a copy of source, or a new source of the specified
+// kind if it's nil.
+func getSourceCopy(source *ValueSource, kind SourceKind) *ValueSource {
+	if source == nil {
+		return &ValueSource{Kind: kind}
+	}
+
+	sourceCopy := *source
+	return &sourceCopy
+}
@@ -0,0 +1,252 @@
+package strongParser
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ALiwoto/ssg/ssg/rangeValues"
+)
+
+// GenerateJSONSchema returns a JSON schema (draft 2020-12) of the config
+// struct type of v (a struct, or a pointer to one, which may be nil), for
+// editor tooling. Sections are objects of the root object, and dotted
+// sections are nested in the object of their parent; for example the key
+// "url" of the section "database.replica" is at database.replica.url. The
+// elements of maps and slices of structs are the properties of the object of
+// their prefix, except for slices mapped to a section pattern.
+//
+// Options are described using their `desc`, `default` and validation tags.
+func GenerateJSONSchema(v any) ([]byte, error) {
+	t := getStructType(v)
+	if t == nil {
+		return nil, &InvalidParseError{reflect.TypeOf(v)}
+	}
+
+	schema := newObjectSchema()
+	schema["$schema"] = jsonSchemaDraft
+	schema["title"] = t.Name()
+	addSchemaFields(schema, t, "", "")
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// addSchemaFields adds the fields of the struct type t to the schema of the
+// root object. section and parentSection have the same meaning as in
+// parseStruct.
+func addSchemaFields(root map[string]any, t reflect.Type, section, parentSection string) {
+	walkFields(t, section, parentSection, "", DefaultMainSection, func(info *fieldInfo) {
+		switch info.kind {
+		case fieldValue:
+			object := getSchemaObject(root, info.section)
+			object["properties"].(map[string]any)[info.key] = getValueSchema(info.valueType, info.field.Tag)
+			if isTrueTag(info.field.Tag.Get("required")) {
+				required, _ := object["required"].([]string)
+				object["required"] = append(required, info.key)
+			}
+		case fieldMap:
+			object := getSchemaObject(root, info.section)
+			object["additionalProperties"] = getValueSchema(info.valueType, info.field.Tag)
+		case fieldStructMap, fieldStructSlice:
+			if isSectionPattern(info.section) {
+				// the sections of a pattern can't be described as an object.
+				return
+			}
+
+			elemType := info.valueType
+			if elemType.Kind() == reflect.Ptr {
+				elemType = elemType.Elem()
+			}
+
+			// the fields of the elements are placed under a temporary root.
+			elemRoot := newObjectSchema()
+			addSchemaFields(elemRoot, elemType, "element", "element")
+			elemSchema := getSchemaObject(elemRoot, "element")
+
+			object := getSchemaObject(root, info.section)
+			object["additionalProperties"] = elemSchema
+			if desc := strings.TrimSpace(info.field.Tag.Get("desc")); desc != "" {
+				object["description"] = desc
+			}
+		}
+	})
+}
+
+// getSchemaObject returns the schema of the object of section under root,
+// creating it (and the objects of its parents) if needed.
+func getSchemaObject(root map[string]any, section string) map[string]any {
+	current := root
+	for _, name := range strings.Split(section, ".") {
+		properties := current["properties"].(map[string]any)
+		child, ok := properties[name].(map[string]any)
+		if !ok {
+			child = newObjectSchema()
+			properties[name] = child
+		}
+
+		current = child
+	}
+
+	return current
+}
+
+func newObjectSchema() map[string]any {
+	return map[string]any{
+		"type":       "object",
+		"properties": make(map[string]any),
+	}
+}
+
+// getValueSchema returns the schema of an option of type t, described by the
+// tags of its field.
+func getValueSchema(t reflect.Type, tag reflect.StructTag) map[string]any {
+	fType := strings.ToLower(tag.Get("type"))
+	schema := getTypeSchema(t, fType)
+	if desc := strings.TrimSpace(tag.Get("desc")); desc != "" {
+		schema["description"] = desc
+	}
+
+	if isSecretField(reflect.StructField{Tag: tag}) {
+		schema["writeOnly"] = true
+	}
+
+	if defaultValue, ok := tag.Lookup("default"); ok {
+		schema["default"] = getSchemaValue(t, fType, defaultValue)
+	}
+
+	// checks other than the length ones apply to the elements of slices.
+	itemType, itemSchema := t, schema
+	if items, ok := schema["items"].(map[string]any); ok {
+		itemType, itemSchema = t.Elem(), items
+	}
+
+	minName, maxName := getBoundNames(schema)
+	if minName != "" {
+		if bound, ok := tag.Lookup("min"); ok {
+			setSchemaBound(schema, minName, bound)
+		}
+
+		if bound, ok := tag.Lookup("max"); ok {
+			setSchemaBound(schema, maxName, bound)
+		}
+
+		if minValue, maxValue, ok := rangeValues.SplitRange(tag.Get("range")); ok {
+			setSchemaBound(schema, minName, minValue)
+			setSchemaBound(schema, maxName, maxValue)
+		}
+	}
+
+	if isTrueTag(tag.Get("nonempty")) {
+		switch schema["type"] {
+		case "array":
+			schema["minItems"] = 1
+		case "string":
+			schema["minLength"] = 1
+		}
+	}
+
+	if oneOf, ok := tag.Lookup("oneof"); ok {
+		var enum []any
+		for _, value := range strings.Fields(strings.ReplaceAll(oneOf, ",", " ")) {
+			enum = append(enum, getSchemaValue(itemType, fType, value))
+		}
+
+		itemSchema["enum"] = enum
+	}
+
+	if pattern, ok := tag.Lookup("regex"); ok && itemSchema["type"] == "string" {
+		itemSchema["pattern"] = pattern
+	}
+
+	if isTrueTag(tag.Get("url")) && itemSchema["type"] == "string" {
+		itemSchema["format"] = "uri"
+	}
+
+	return schema
+}
+
+// getTypeSchema returns the schema of the values of type t.
+func getTypeSchema(t reflect.Type, fType string) map[string]any {
+	if fType == "rune" {
+		return map[string]any{"type": "string", "maxLength": 1}
+	}
+
+	if getDecoder(t) != nil {
+		schema := map[string]any{"type": "string"}
+		if t.String() == "url.URL" {
+			schema["format"] = "uri"
+		}
+
+		return schema
+	}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Complex64, reflect.Complex128:
+		return map[string]any{"type": "string"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": getTypeSchema(t.Elem(), "")}
+	}
+
+	return make(map[string]any)
+}
+
+// getBoundNames returns the names of the keywords the min and max tags of a
+// value with schema are translated to, or empty strings if there are none.
+func getBoundNames(schema map[string]any) (string, string) {
+	switch schema["type"] {
+	case "integer", "number":
+		return "minimum", "maximum"
+	case "array":
+		return "minItems", "maxItems"
+	case "string":
+		if schema["format"] == nil && schema["maxLength"] == nil {
+			return "minLength", "maxLength"
+		}
+	}
+
+	return "", ""
+}
+
+// setSchemaBound sets the keyword name of schema to the numeric value of
+// bound, if it's a number.
+func setSchemaBound(schema map[string]any, name, bound string) {
+	value, err := strconv.ParseFloat(strings.TrimSpace(bound), 64)
+	if err == nil {
+		schema[name] = value
+	}
+}
+
+// getSchemaValue converts value, the text of a tag, to the JSON value of an
+// option of type t; values that can't be converted are kept as strings.
+func getSchemaValue(t reflect.Type, fType, value string) any {
+	if fType == "rune" || getDecoder(t) != nil {
+		return value
+	}
+
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		var values []any
+		for _, element := range parseToStringArray(value, false) {
+			if element != "" {
+				values = append(values, getSchemaValue(t.Elem(), "", element))
+			}
+		}
+
+		return values
+	}
+
+	converted, err := convertString(t, fType, value)
+	if err != nil || t.Kind() == reflect.Complex64 || t.Kind() == reflect.Complex128 {
+		return value
+	}
+
+	return converted.Interface()
+}
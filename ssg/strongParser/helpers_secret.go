@@ -0,0 +1,91 @@
+package strongParser
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// EncryptValue encrypts value with AES-GCM using key, which must be 16, 24
+// or 32 bytes long, and returns it in the form used in config files: "enc:"
+// followed by the base64-encoded nonce and ciphertext. Such values are
+// decrypted when parsed with the ResolveSecrets option.
+func EncryptValue(value string, key []byte) (string, error) {
+	gcm, err := newSecretCipher(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(value), nil)
+	return encryptedValuePrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptValue decrypts a value returned by EncryptValue, with or without
+// its "enc:" prefix.
+func DecryptValue(value string, key []byte) (string, error) {
+	gcm, err := newSecretCipher(key)
+	if err != nil {
+		return "", err
+	}
+
+	value = strings.TrimPrefix(strings.TrimSpace(value), encryptedValuePrefix)
+	sealed, err := base64.StdEncoding.DecodeString(value)
+	if err != nil || len(sealed) < gcm.NonceSize() {
+		return "", ErrInvalidSecret
+	}
+
+	nonceSize := gcm.NonceSize()
+	plaintext, err := gcm.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		// a wrong key or a modified value.
+		return "", ErrInvalidSecret
+	}
+
+	return string(plaintext), nil
+}
+
+func newSecretCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSecretKey, err)
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// readSecretFile returns the content of the named file, without its trailing
+// newlines.
+func readSecretFile(path string) (string, error) {
+	content, err := os.ReadFile(strings.TrimSpace(path))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+// isSecretField returns true if the field is tagged `secret:"true"`; its
+// values are masked by Dump, and in the errors and changes that mention
+// them.
+func isSecretField(fByName reflect.StructField) bool {
+	return isTrueTag(fByName.Tag.Get("secret"))
+}
+
+// maskValue returns secretMask instead of value if isSecret is true.
+func maskValue(value string, isSecret bool) string {
+	if isSecret {
+		return secretMask
+	}
+
+	return value
+}
@@ -0,0 +1,123 @@
+package strongParser
+
+import (
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// parseStructSlice fills a []SubStruct (or []*SubStruct) field with a struct
+// for each of its sections, in the order they appear in the source (see
+// getSliceSections). Elements that implement SectionValue get the name of
+// their section. The field is left unchanged if there is no such section.
+func (p *ConfigParser) parseStructSlice(
+	currentField reflect.Value,
+	fByName reflect.StructField,
+	parentSection string,
+) (bool, error) {
+	sections := p.getSliceSections(getNestedSectionName(fByName, parentSection))
+	if len(sections) == 0 {
+		return false, nil
+	}
+
+	sliceType := currentField.Type()
+	elemType := sliceType.Elem()
+	slice := reflect.MakeSlice(sliceType, 0, len(sections))
+	for _, sectionName := range sections {
+		elemValue := reflect.New(elemType).Elem()
+		structValue := elemValue
+		if elemType.Kind() == reflect.Ptr {
+			elemValue = reflect.New(elemType.Elem())
+			structValue = elemValue.Elem()
+		}
+
+		err := p.parseStruct(structValue, sectionName, sectionName)
+		if err != nil {
+			return false, err
+		}
+
+		if validS, ok := structValue.Addr().Interface().(SectionValue); ok {
+			validS.SetSectionName(sectionName)
+		}
+
+		slice = reflect.Append(slice, elemValue)
+	}
+
+	currentField.Set(slice)
+	return true, nil
+}
+
+// getSliceSections returns the names of the sections of a slice of structs
+// mapped to pattern, in order. If pattern has the wildcards of path.Match
+// (such as "server.*"), the sections are the ones that match it; otherwise
+// they are the ones named "pattern.name" or "pattern:name" (such as
+// [server.alpha] or [server:1] for "server"). Sections under the section of
+// an element (such as [server.alpha.tls]) belong to its nested structs.
+func (p *ConfigParser) getSliceSections(pattern string) []string {
+	var sections []string
+	for _, sectionName := range p.sectionOrder {
+		if matchSliceSection(pattern, sectionName) {
+			sections = append(sections, sectionName)
+		}
+	}
+
+	elements := sections[:0]
+	for _, current := range sections {
+		isNested := false
+		for _, other := range sections {
+			if strings.HasPrefix(current, other+".") {
+				isNested = true
+				break
+			}
+		}
+
+		if !isNested {
+			elements = append(elements, current)
+		}
+	}
+
+	return elements
+}
+
+// matchSliceSection returns true if the section name matches the pattern of
+// a slice of structs (see getSliceSections).
+func matchSliceSection(pattern, name string) bool {
+	if isSectionPattern(pattern) {
+		matched, _ := path.Match(pattern, name)
+		return matched
+	}
+
+	for _, separator := range []string{".", ":"} {
+		suffix, ok := strings.CutPrefix(name, pattern+separator)
+		if ok && suffix != "" && !strings.Contains(suffix, ".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isSectionPattern returns true if the section name of a slice of structs
+// has the wildcards of path.Match.
+func isSectionPattern(pattern string) bool {
+	return strings.ContainsAny(pattern, `*?[\`)
+}
+
+// getSliceSectionName returns the name of the section of the element at
+// index of a slice of structs mapped to pattern, when it's written to a
+// config file: "pattern.1" for the first element, or the pattern with its
+// first wildcard replaced by the number.
+func getSliceSectionName(pattern string, index int) string {
+	number := strconv.Itoa(index + 1)
+	if !isSectionPattern(pattern) {
+		return pattern + "." + number
+	}
+
+	wildcard := strings.IndexAny(pattern, "*?")
+	if wildcard == -1 {
+		return pattern
+	}
+
+	return pattern[:wildcard] + number + strings.ReplaceAll(pattern[wildcard+1:], "*", "")
+}
@@ -0,0 +1,60 @@
+package strongParser
+
+import "sort"
+
+// getSuggestion returns the known key closest to key, if it's close enough
+// to be a typo of it.
+func getSuggestion(key string, knownKeys map[string]bool) string {
+	candidates := make([]string, 0, len(knownKeys))
+	for current := range knownKeys {
+		candidates = append(candidates, current)
+	}
+
+	// ties are broken alphabetically.
+	sort.Strings(candidates)
+
+	maxDistance := max(2, len(key)/3)
+	suggestion := ""
+	for _, current := range candidates {
+		distance := getEditDistance(key, current)
+		if distance <= maxDistance && distance < len(key) {
+			suggestion = current
+			maxDistance = distance - 1
+		}
+	}
+
+	return suggestion
+}
+
+// getEditDistance returns the Levenshtein distance between a and b, where a
+// swap of two adjacent characters counts as a single edit.
+func getEditDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	// prev2, prev and current are the last three rows of the distance matrix.
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(prev[j]+1, current[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				current[j] = min(current[j], prev2[j-2]+1)
+			}
+		}
+
+		prev2, prev, current = prev, current, prev2
+	}
+
+	return prev[len(rb)]
+}
@@ -0,0 +1,163 @@
+package strongParser
+
+import (
+	"reflect"
+	"strings"
+)
+
+// GenerateTemplate returns an example config file for the struct type of v
+// (a struct, or a pointer to one, which may be nil), as read by ParseConfig.
+// Each option is set to its `default` tag (or left empty), after comments
+// that show its `desc` tag, its type and validation tags, and the
+// environment variables it can be read from instead. Map fields get a
+// commented example of their options, or an example section for maps and
+// slices of structs.
+func GenerateTemplate(v any) string {
+	t := getStructType(v)
+	if t == nil {
+		return ""
+	}
+
+	var order []string
+	sections := make(map[string][]string)
+	add := func(section string, lines ...string) {
+		if _, exists := sections[section]; !exists {
+			order = append(order, section)
+		}
+
+		sections[section] = append(sections[section], lines...)
+	}
+
+	addTemplateFields(t, "", "", add)
+
+	var sb strings.Builder
+	for i, section := range order {
+		if i != 0 {
+			sb.WriteByte('\n')
+		}
+
+		sb.WriteString("[" + section + "]\n")
+		for j, line := range sections[section] {
+			if j != 0 && strings.HasPrefix(line, "\x00") {
+				// the start of an option, after the previous one.
+				sb.WriteByte('\n')
+			}
+
+			sb.WriteString(strings.TrimPrefix(line, "\x00") + "\n")
+		}
+	}
+
+	return sb.String()
+}
+
+// addTemplateFields adds the options of the fields of the struct type t to
+// their sections using add; the first line of each option starts with a zero
+// byte. section and parentSection have the same meaning as in parseStruct.
+func addTemplateFields(t reflect.Type, section, parentSection string, add func(section string, lines ...string)) {
+	walkFields(t, section, parentSection, "", DefaultMainSection, func(info *fieldInfo) {
+		lines := getTemplateComments(info)
+		switch info.kind {
+		case fieldValue:
+			lines = append(lines, info.key+strings.TrimRight(" = "+info.field.Tag.Get("default"), " "))
+			lines[0] = "\x00" + lines[0]
+			add(info.section, lines...)
+		case fieldMap:
+			lines = append(lines, "# name = value")
+			lines[0] = "\x00" + lines[0]
+			add(info.section, lines...)
+		case fieldStructMap, fieldStructSlice:
+			elemType := info.valueType
+			if elemType.Kind() == reflect.Ptr {
+				elemType = elemType.Elem()
+			}
+
+			// an example element; its fields are added to the same section.
+			exampleSection := info.section + ".example"
+			if info.kind == fieldStructSlice {
+				exampleSection = getSliceSectionName(info.section, 0)
+			}
+
+			lines[0] = "\x00" + lines[0]
+			add(exampleSection, lines...)
+			addTemplateFields(elemType, exampleSection, exampleSection, add)
+		}
+	})
+}
+
+// getTemplateComments returns the comment lines that describe a field in a
+// generated template.
+func getTemplateComments(info *fieldInfo) []string {
+	var lines []string
+	if desc := strings.TrimSpace(info.field.Tag.Get("desc")); desc != "" {
+		for _, line := range strings.Split(desc, "\n") {
+			lines = append(lines, strings.TrimRight("# "+strings.TrimSpace(line), " "))
+		}
+	}
+
+	tag := info.field.Tag
+	switch info.kind {
+	case fieldMap:
+		lines = append(lines, "# one option per entry of type "+getTypeName(info))
+	case fieldStructMap:
+		lines = append(lines, "# an example entry; one ["+info.section+".<name>] section per entry")
+		return lines
+	case fieldStructSlice:
+		if isSectionPattern(info.section) {
+			lines = append(lines, "# an example element; one section matching ["+info.section+"] per element")
+		} else {
+			lines = append(lines, "# an example element; one ["+info.section+".<name>] or ["+
+				info.section+":<name>] section per element")
+		}
+
+		return lines
+	default:
+		details := []string{"type: " + getTypeName(info)}
+		details = append(details, getConstraints(tag)...)
+		lines = append(lines, "# "+strings.Join(details, "; "))
+		lines = append(lines, "# env: "+strings.Join(getFieldEnvNames(info), ", "))
+		return lines
+	}
+
+	if constraints := getConstraints(tag); len(constraints) != 0 {
+		lines = append(lines, "# "+strings.Join(constraints, "; "))
+	}
+
+	return lines
+}
+
+// getConstraints returns the description of the validation tags in tag.
+func getConstraints(tag reflect.StructTag) []string {
+	var constraints []string
+	if isTrueTag(tag.Get("required")) {
+		constraints = append(constraints, "required")
+	}
+
+	if isTrueTag(tag.Get("nonempty")) {
+		constraints = append(constraints, "non-empty")
+	}
+
+	for _, name := range []string{"min", "max", "range", "regex"} {
+		if value, ok := tag.Lookup(name); ok {
+			constraints = append(constraints, name+": "+value)
+		}
+	}
+
+	if oneOf, ok := tag.Lookup("oneof"); ok {
+		allowed := strings.Fields(strings.ReplaceAll(oneOf, ",", " "))
+		constraints = append(constraints, "one of: "+strings.Join(allowed, ", "))
+	}
+
+	if isTrueTag(tag.Get("url")) {
+		constraints = append(constraints, "absolute URL")
+	}
+
+	if isTrueTag(tag.Get("path_exists")) {
+		constraints = append(constraints, "existing path")
+	}
+
+	if isSecretField(reflect.StructField{Tag: tag}) {
+		constraints = append(constraints, "secret")
+	}
+
+	return constraints
+}
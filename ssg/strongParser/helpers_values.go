@@ -0,0 +1,271 @@
+package strongParser
+
+import (
+	"strings"
+	"unicode"
+)
+
+// parseOptionValue converts the value of an option as written in the source
+// (possibly spanning several lines) to its actual value, according to opt.
+// comment is the inline comment after the value, quoted is true if the value
+// is a quoted one, and open is true if the value continues on the next line
+// (after a trailing backslash, or in an unterminated triple-quoted block).
+func parseOptionValue(rawValue string, opt *ConfigParserOptions) (value, comment string, quoted, open bool) {
+	if opt.QuotedValues {
+		value, rest, quoted, open := parseQuotedValue(rawValue)
+		if quoted {
+			return value, rest, true, open
+		}
+	}
+
+	var sb strings.Builder
+	continued := false
+	for i, line := range strings.Split(rawValue, "\n") {
+		if i != 0 {
+			// the indentation of continuation lines is not a part of the value.
+			line = strings.TrimLeftFunc(line, unicode.IsSpace)
+			if !continued {
+				sb.WriteByte('\n')
+			}
+		}
+
+		if opt.InlineComments {
+			var lineComment string
+			line, lineComment = cutInlineComment(line)
+			if i == 0 {
+				comment = lineComment
+			}
+		}
+
+		line = strings.TrimRightFunc(line, unicode.IsSpace)
+		continued = opt.MultilineValues && strings.HasSuffix(line, "\\")
+		if continued {
+			line = strings.TrimSuffix(line, "\\")
+		}
+
+		sb.WriteString(line)
+	}
+
+	return strings.TrimSpace(sb.String()), comment, false, continued
+}
+
+// parseQuotedValue parses a value that starts with a quote. rest is the text
+// after the closing quote. quoted is false if rawValue is not a complete
+// single-line quoted value or a triple-quoted block; open is true if it's an
+// unterminated triple-quoted block.
+func parseQuotedValue(rawValue string) (value, rest string, quoted, open bool) {
+	for _, quote := range []string{`"""`, `'''`} {
+		if !strings.HasPrefix(rawValue, quote) {
+			continue
+		}
+
+		body := rawValue[len(quote):]
+		end := indexUnescaped(body, quote)
+		if end == -1 {
+			return "", "", true, true
+		}
+
+		// a newline right after the opening quotes is not a part of the value.
+		inner := strings.TrimPrefix(strings.TrimPrefix(body[:end], "\r"), "\n")
+		return unescapeValue(inner), body[end+len(quote):], true, false
+	}
+
+	if rawValue == "" || (rawValue[0] != '"' && rawValue[0] != '\'') {
+		return "", "", false, false
+	}
+
+	quote := rawValue[:1]
+	end := indexUnescaped(rawValue[1:], quote)
+	if end == -1 || strings.Contains(rawValue[1:end+1], "\n") {
+		return "", "", false, false
+	}
+
+	return unescapeValue(rawValue[1 : end+1]), rawValue[end+2:], true, false
+}
+
+// indexUnescaped returns the index of the first instance of substr in s that
+// is not escaped with a backslash, or -1 if there is none.
+func indexUnescaped(s, substr string) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+
+		if strings.HasPrefix(s[i:], substr) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// unescapeValue replaces the escape sequences of a quoted value. Unknown
+// escape sequences are kept as they are.
+func unescapeValue(value string) string {
+	if !strings.Contains(value, "\\") {
+		return value
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i+1 == len(value) {
+			sb.WriteByte(value[i])
+			continue
+		}
+
+		i++
+		switch value[i] {
+		case 'n':
+			sb.WriteByte('\n')
+		case 't':
+			sb.WriteByte('\t')
+		case 'r':
+			sb.WriteByte('\r')
+		case '\\', '"', '\'':
+			sb.WriteByte(value[i])
+		default:
+			sb.WriteByte('\\')
+			sb.WriteByte(value[i])
+		}
+	}
+
+	return sb.String()
+}
+
+// escapeValue is the inverse of unescapeValue, for double quoted values.
+func escapeValue(value string) string {
+	return strings.NewReplacer(
+		"\\", "\\\\",
+		"\"", "\\\"",
+		"\n", "\\n",
+		"\t", "\\t",
+		"\r", "\\r",
+	).Replace(value)
+}
+
+// splitQuotedArray splits the value of an array option like parseToStringArray,
+// except that elements can be single or double quoted to contain separators
+// (see quoteArrayElement). A quote that isn't at the start of an element, or
+// is never closed, is a part of the element.
+func splitQuotedArray(value string) []string {
+	var elements []string
+	for i := 0; i < len(value); {
+		if isArraySeparator(value[i]) {
+			i++
+			continue
+		}
+
+		if value[i] == '"' || value[i] == '\'' {
+			end := indexUnescaped(value[i+1:], value[i:i+1])
+			if end != -1 {
+				elements = append(elements, unescapeValue(value[i+1:i+1+end]))
+				i += end + 2
+				continue
+			}
+		}
+
+		start := i
+		for i < len(value) && !isArraySeparator(value[i]) {
+			i++
+		}
+
+		elements = append(elements, value[start:i])
+	}
+
+	return elements
+}
+
+func isArraySeparator(c byte) bool {
+	return c == ',' || c == '[' || c == ']' || c == ' ' || c == '\t' ||
+		c == '\r' || c == '\n'
+}
+
+// joinArrayElements returns the value of an array option with the given
+// elements, which parseToStringArray splits back into them: if any of them
+// needs quotes, the list is written in brackets (see isJoinedArray).
+func joinArrayElements(elements []string) string {
+	anyQuoted := false
+	quotedElements := make([]string, 0, len(elements))
+	for _, element := range elements {
+		element, quoted := quoteArrayElement(element)
+		anyQuoted = anyQuoted || quoted
+		quotedElements = append(quotedElements, element)
+	}
+
+	value := strings.Join(quotedElements, ", ")
+	if anyQuoted {
+		// the brackets keep a value that starts with a quoted element from
+		// being taken as a single quoted value.
+		value = "[" + value + "]"
+	}
+
+	return value
+}
+
+// isJoinedArray returns true if value is a list with quoted elements, as
+// written by joinArrayElements: it's in brackets, and has double quotes.
+func isJoinedArray(value string) bool {
+	return strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") &&
+		strings.Contains(value, `"`)
+}
+
+// quoteArrayElement returns element in the form it has to be written in an
+// array value: elements that are empty or contain separators or quotes get
+// double quoted, so that splitQuotedArray reads them back as they are.
+func quoteArrayElement(element string) (string, bool) {
+	if element != "" && !strings.ContainsAny(element, ", \t\r\n[]\"'") {
+		return element, false
+	}
+
+	return `"` + escapeValue(element) + `"`, true
+}
+
+// cutInlineComment splits line into its value and an inline comment, which
+// starts with a ; or # at the beginning of line or after a space. The spaces
+// before the comment are a part of comment.
+func cutInlineComment(line string) (string, string) {
+	for i := 0; i < len(line); i++ {
+		if line[i] != ';' && line[i] != '#' {
+			continue
+		}
+
+		if i != 0 && line[i-1] != ' ' && line[i-1] != '\t' {
+			continue
+		}
+
+		start := len(strings.TrimRightFunc(line[:i], unicode.IsSpace))
+		return line[:start], line[start:]
+	}
+
+	return line, ""
+}
+
+// formatOptionValue returns value in the form it has to be written in a
+// config file, so that parsing it with opt gives value back; values that
+// can't be written as they are get quoted if opt allows it.
+func formatOptionValue(value string, opt *ConfigParserOptions) string {
+	if opt == nil || value == "" {
+		return value
+	}
+
+	_, comment := cutInlineComment(value)
+	needsQuotes := value != strings.TrimSpace(value) ||
+		strings.Contains(value, "\n") ||
+		(opt.InlineComments && comment != "") ||
+		(opt.MultilineValues && strings.HasSuffix(value, "\\")) ||
+		(opt.QuotedValues && strings.ContainsAny(value[:1], `"'`))
+	if !needsQuotes {
+		return value
+	}
+
+	if opt.QuotedValues {
+		return `"` + escapeValue(value) + `"`
+	}
+
+	if opt.MultilineValues {
+		return strings.ReplaceAll(value, "\n", "\n    ")
+	}
+
+	return value
+}
@@ -0,0 +1,173 @@
+package strongParser
+
+import (
+	"os"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// WatchConfig parses the config file at path into a new T, and keeps watching
+// it for changes: on Linux using inotify, elsewhere (or if opts.Polling is
+// set) by polling its modification time and size. The files it includes and
+// its profile overlay are watched as well.
+//
+// When the file changes, it's parsed and validated again, and the new value
+// is swapped in atomically; onChange (if not nil) is then called with the old
+// and the new values, and the fields that differ between them. If the new
+// content is invalid, it's rejected and the last good config is kept; the
+// error is reported to opts.OnError.
+//
+// The current value is returned by the Current method of the watcher, which
+// must be closed when it's not needed anymore. Values passed to onChange and
+// returned by Current must not be modified.
+func WatchConfig[T any](
+	path string,
+	opts *WatchOptions,
+	onChange func(old, new *T, diff []Change),
+) (*ConfigWatcher[T], error) {
+	if opts == nil {
+		opts = &WatchOptions{}
+	}
+
+	w := &ConfigWatcher[T]{
+		path:      path,
+		options:   opts,
+		onChange:  onChange,
+		reloadMut: &sync.Mutex{},
+		events:    make(chan struct{}, 1),
+		done:      make(chan struct{}),
+		closeOnce: &sync.Once{},
+		watchMut:  &sync.Mutex{},
+	}
+
+	value, files, err := w.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	w.current.Store(value)
+
+	err = w.watchFiles(files)
+	if err != nil {
+		return nil, err
+	}
+
+	go w.run()
+	return w, nil
+}
+
+// startPolling checks the files at paths every interval, and sends to events
+// when the modification time or size of any of them changes.
+func startPolling(paths []string, interval time.Duration, events chan<- struct{}) func() error {
+	stop := make(chan struct{})
+	lastInfos := make([]os.FileInfo, len(paths))
+	for i, path := range paths {
+		lastInfos[i], _ = os.Stat(path)
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+
+			for i, path := range paths {
+				if isFileChanged(path, &lastInfos[i]) {
+					notifyEvent(events)
+				}
+			}
+		}
+	}()
+
+	return func() error {
+		close(stop)
+		return nil
+	}
+}
+
+// isFileChanged returns true if the file at path was created, removed, or
+// changed its modification time or size since lastInfo, which is updated.
+func isFileChanged(path string, lastInfo *os.FileInfo) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		changed := *lastInfo != nil
+		*lastInfo = nil
+		return changed
+	}
+
+	if *lastInfo == nil || !info.ModTime().Equal((*lastInfo).ModTime()) ||
+		info.Size() != (*lastInfo).Size() {
+		*lastInfo = info
+		return true
+	}
+
+	return false
+}
+
+// notifyEvent sends to events without blocking; a pending event is enough.
+func notifyEvent(events chan<- struct{}) {
+	select {
+	case events <- struct{}{}:
+	default:
+	}
+}
+
+// getChanges returns the exported fields that differ between oldValue and
+// newValue, which must be of the same type. Nested structs are compared field
+// by field, any other value as a whole; the values of secret fields are
+// masked.
+func getChanges(oldValue, newValue any) []Change {
+	return appendChanges(nil, "", reflect.ValueOf(oldValue), reflect.ValueOf(newValue), false)
+}
+
+func appendChanges(changes []Change, path string, oldValue, newValue reflect.Value, isSecret bool) []Change {
+	if oldValue.Kind() == reflect.Ptr && isStructType(oldValue.Type()) &&
+		!oldValue.IsNil() && !newValue.IsNil() {
+		oldValue = oldValue.Elem()
+		newValue = newValue.Elem()
+	}
+
+	if oldValue.Kind() == reflect.Struct && isStructType(oldValue.Type()) {
+		myType := oldValue.Type()
+		for i := 0; i < myType.NumField(); i++ {
+			fByName := myType.Field(i)
+			if !fByName.IsExported() {
+				continue
+			}
+
+			fieldPath := fByName.Name
+			if path != "" {
+				fieldPath = path + "." + fByName.Name
+			}
+
+			changes = appendChanges(changes, fieldPath, oldValue.Field(i), newValue.Field(i),
+				isSecretField(fByName))
+		}
+
+		return changes
+	}
+
+	if reflect.DeepEqual(oldValue.Interface(), newValue.Interface()) {
+		return changes
+	}
+
+	if isSecret {
+		return append(changes, Change{
+			Field: path,
+			Old:   secretMask,
+			New:   secretMask,
+		})
+	}
+
+	return append(changes, Change{
+		Field: path,
+		Old:   oldValue.Interface(),
+		New:   newValue.Interface(),
+	})
+}
@@ -0,0 +1,78 @@
+package strongParser
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// startFileWatcher uses inotify to send to events when any of the files at
+// paths is written, created, replaced or removed. The directories of the
+// files are watched, so editors that replace a file are handled as well.
+func startFileWatcher(paths []string, events chan<- struct{}) (func() error, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_NONBLOCK | syscall.IN_CLOEXEC)
+	if err != nil {
+		return nil, errWatchNotSupported
+	}
+
+	// names holds the names of the watched files, by the watch descriptor of
+	// their directory.
+	names := make(map[int32]map[string]bool)
+	const mask = syscall.IN_CLOSE_WRITE | syscall.IN_MODIFY | syscall.IN_CREATE |
+		syscall.IN_DELETE | syscall.IN_MOVED_TO | syscall.IN_MOVED_FROM
+	for _, path := range paths {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			_ = syscall.Close(fd)
+			return nil, err
+		}
+
+		// adding the same directory again returns its descriptor.
+		wd, err := syscall.InotifyAddWatch(fd, filepath.Dir(absPath), mask)
+		if err != nil {
+			_ = syscall.Close(fd)
+			return nil, err
+		}
+
+		if names[int32(wd)] == nil {
+			names[int32(wd)] = make(map[string]bool)
+		}
+
+		names[int32(wd)][filepath.Base(absPath)] = true
+	}
+
+	// a non-blocking fd is handled by the runtime poller, so closing the file
+	// stops the pending Read below.
+	file := os.NewFile(uintptr(fd), "inotify")
+
+	go func() {
+		buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+		for {
+			n, err := file.Read(buf)
+			if err != nil {
+				return
+			}
+
+			for offset := 0; offset+syscall.SizeofInotifyEvent <= n; {
+				// the Wd field comes first, and the Len field holds the
+				// length of the name after the event.
+				wd := int32(binary.NativeEndian.Uint32(buf[offset:]))
+				nameLen := int(binary.NativeEndian.Uint32(buf[offset+12:]))
+				nameStart := offset + syscall.SizeofInotifyEvent
+				offset = nameStart + nameLen
+				if offset > n {
+					break
+				}
+
+				eventName := strings.TrimRight(string(buf[nameStart:offset]), "\x00")
+				if names[wd][eventName] {
+					notifyEvent(events)
+				}
+			}
+		}
+	}()
+
+	return file.Close, nil
+}
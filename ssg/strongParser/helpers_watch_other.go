@@ -0,0 +1,8 @@
+//go:build !linux
+
+package strongParser
+
+// startFileWatcher is only implemented on Linux; other platforms use polling.
+func startFileWatcher(paths []string, events chan<- struct{}) (func() error, error) {
+	return nil, errWatchNotSupported
+}
@@ -0,0 +1,278 @@
+package strongParser
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ALiwoto/ssg/ssg/caseUtils"
+)
+
+// Marshal returns the INI representation of the struct pointed to by v (or
+// of v itself, if it's a struct), using the same tags as ParseConfig:
+// `section`, `key` and `type`. Fields without a section are written to the
+// main section, and nested structs are written to their own sections.
+// nil pointers are skipped.
+func Marshal(v any) ([]byte, error) {
+	return marshal(v, false)
+}
+
+// Dump returns the INI representation of v like Marshal, except that the
+// values of the fields tagged `secret:"true"` are masked; use it to print or
+// log a config.
+func Dump(v any) (string, error) {
+	b, err := marshal(v, true)
+	return string(b), err
+}
+
+func marshal(v any, maskSecrets bool) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return nil, &InvalidParseError{reflect.TypeOf(v)}
+	}
+
+	p := NewConfigParser()
+	marshalStruct(p, rv, "", "", maskSecrets)
+
+	return []byte(p.String()), nil
+}
+
+// marshalStruct adds the exported fields of the struct value rv to p.
+// section and parentSection have the same meaning as in parseStruct.
+// If maskSecrets is true, the values of secret fields are masked.
+func marshalStruct(p *ConfigParser, rv reflect.Value, section, parentSection string, maskSecrets bool) {
+	myType := rv.Type()
+	for i := 0; i < rv.NumField(); i++ {
+		fByName := myType.Field(i)
+		if isEmbeddedStruct(fByName) {
+			embedded := rv.Field(i)
+			if embedded.Kind() == reflect.Ptr {
+				if embedded.IsNil() {
+					continue
+				}
+
+				embedded = embedded.Elem()
+			}
+
+			marshalStruct(p, embedded, section, parentSection, maskSecrets)
+			continue
+		}
+
+		if !fByName.IsExported() {
+			continue
+		}
+
+		marshalField(p, rv.Field(i), fByName, section, parentSection, maskSecrets)
+	}
+}
+
+func marshalField(
+	p *ConfigParser,
+	currentField reflect.Value,
+	fByName reflect.StructField,
+	section, parentSection string,
+	maskSecrets bool,
+) {
+	isDecodable := getDecoder(currentField.Type()) != nil
+	switch {
+	case isDecodable:
+		// written as a single value, in its text form.
+	case currentField.Kind() == reflect.Struct:
+		nestedSection := getNestedSectionName(fByName, parentSection)
+		marshalStruct(p, currentField, nestedSection, nestedSection, maskSecrets)
+		return
+	case currentField.Kind() == reflect.Map:
+		mapSection := getNestedSectionName(fByName, parentSection)
+		marshalMap(p, currentField, mapSection, maskSecrets && isSecretField(fByName), maskSecrets)
+		return
+	case currentField.Kind() == reflect.Slice && isStructType(currentField.Type().Elem()):
+		marshalSlice(p, currentField, getNestedSectionName(fByName, parentSection), maskSecrets)
+		return
+	case currentField.Kind() == reflect.Ptr:
+		if currentField.IsNil() {
+			return
+		}
+
+		if currentField.Elem().Kind() == reflect.Struct {
+			nestedSection := getNestedSectionName(fByName, parentSection)
+			p.getOrAddSection(nestedSection)
+			marshalStruct(p, currentField.Elem(), nestedSection, nestedSection, maskSecrets)
+			return
+		}
+
+		marshalField(p, currentField.Elem(), fByName, section, parentSection, maskSecrets)
+		return
+	}
+
+	fType := strings.ToLower(fByName.Tag.Get("type"))
+	value, ok := formatFieldValue(currentField, fType)
+	if !ok {
+		return
+	}
+
+	value = maskValue(value, maskSecrets && isSecretField(fByName))
+
+	if section == "" {
+		section = fByName.Tag.Get("section")
+	}
+
+	if section == "" {
+		section = DefaultMainSection
+	}
+
+	key := fByName.Tag.Get("key")
+	if key == "" {
+		key = caseUtils.ToSnakeCase(fByName.Name)
+	}
+
+	_ = p.getOrAddSection(section).Add(key, value)
+}
+
+// marshalMap adds the entries of a map field to p, the same way parseMap
+// reads them: scalar values as the options of mapSection, and structs as
+// sections named "mapSection.key". Keys are written in sorted order.
+// If isSecret is true, scalar values are masked; maskSecrets applies to the
+// fields of struct values.
+func marshalMap(p *ConfigParser, currentField reflect.Value, mapSection string, isSecret, maskSecrets bool) {
+	if currentField.Type().Key().Kind() != reflect.String || currentField.Len() == 0 {
+		return
+	}
+
+	keys := currentField.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+
+	isStruct := isStructType(currentField.Type().Elem())
+	for _, key := range keys {
+		value := currentField.MapIndex(key)
+		if !isStruct {
+			if strValue, ok := formatFieldValue(value, ""); ok {
+				_ = p.getOrAddSection(mapSection).Add(key.String(), maskValue(strValue, isSecret))
+			}
+
+			continue
+		}
+
+		if value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				continue
+			}
+
+			value = value.Elem()
+		}
+
+		elemSection := mapSection + "." + key.String()
+		p.getOrAddSection(elemSection)
+		marshalStruct(p, value, elemSection, elemSection, maskSecrets)
+	}
+}
+
+// marshalSlice adds the elements of a slice of structs to p, each one to its
+// own section: the one returned by its SectionValue implementation, if any,
+// or the one named by getSliceSectionName.
+func marshalSlice(p *ConfigParser, currentField reflect.Value, pattern string, maskSecrets bool) {
+	for i := 0; i < currentField.Len(); i++ {
+		value := currentField.Index(i)
+		if value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				continue
+			}
+
+			value = value.Elem()
+		}
+
+		elemSection := ""
+		if validS, ok := value.Addr().Interface().(SectionValue); ok {
+			elemSection = validS.GetSectionName()
+		}
+
+		if elemSection == "" {
+			elemSection = getSliceSectionName(pattern, i)
+		}
+
+		p.getOrAddSection(elemSection)
+		marshalStruct(p, value, elemSection, elemSection, maskSecrets)
+	}
+}
+
+// formatFieldValue converts a field value to its string form in a config
+// file. It returns false if the kind of the value is not supported.
+func formatFieldValue(v reflect.Value, fType string) (string, bool) {
+	if text, ok := formatTextValue(v); ok {
+		return text, true
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return "", false
+		}
+
+		return formatFieldValue(v.Elem(), fType)
+	case reflect.String:
+		return v.String(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if fType == "rune" {
+			return string(rune(v.Int())), true
+		}
+
+		return strconv.FormatInt(v.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), true
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), true
+	case reflect.Complex64, reflect.Complex128:
+		return strconv.FormatComplex(v.Complex(), 'g', -1, v.Type().Bits()), true
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), true
+	case reflect.Array, reflect.Slice:
+		elements := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			element, ok := formatFieldValue(v.Index(i), fType)
+			if !ok {
+				return "", false
+			}
+
+			elements = append(elements, element)
+		}
+
+		return joinArrayElements(elements), true
+	}
+
+	return "", false
+}
+
+// formatTextValue converts v to its text form if it implements
+// encoding.TextMarshaler, or if it has a decoder and implements fmt.Stringer
+// (such as time.Duration and url.URL).
+func formatTextValue(v reflect.Value) (string, bool) {
+	if v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		return "", false
+	}
+
+	if !v.CanAddr() {
+		addressable := reflect.New(v.Type()).Elem()
+		addressable.Set(v)
+		v = addressable
+	}
+
+	switch value := v.Addr().Interface().(type) {
+	case encoding.TextMarshaler:
+		text, err := value.MarshalText()
+		return string(text), err == nil
+	case fmt.Stringer:
+		if getDecoder(v.Type()) != nil {
+			return value.String(), true
+		}
+	}
+
+	return "", false
+}
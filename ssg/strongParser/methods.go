@@ -3,11 +3,11 @@ package strongParser
 import (
 	"errors"
 	"fmt"
-	"os"
 	"reflect"
 	"sort"
 	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/ALiwoto/ssg/ssg/commonUtils"
 	"github.com/ALiwoto/ssg/ssg/rangeValues"
@@ -17,6 +17,12 @@ func (p *ConfigParser) isDefaultSection(section string) bool {
 	return section == defaultSectionName
 }
 
+// SetOptions sets the options used by this parser, such as whether values
+// are interpolated by Get.
+func (p *ConfigParser) SetOptions(opt *ConfigParserOptions) {
+	p.options = opt
+}
+
 // Defaults returns the items in the map used for default values.
 func (p *ConfigParser) Defaults() Dict {
 	return p.defaults.Items()
@@ -73,7 +79,30 @@ func (p *ConfigParser) Options(section string) ([]string, error) {
 // Returns an error if a section does not exist
 // Returns an error if the option does not exist either in the section or in
 // the defaults
+//
+// If interpolation is enabled (see ConfigParserOptions.Interpolate), the
+// references in the value are expanded, and if secrets are enabled (see
+// ConfigParserOptions.ResolveSecrets), secret values are resolved; use GetRaw
+// to get the value as is.
 func (p *ConfigParser) Get(section, option string) (string, error) {
+	value, err := p.GetRaw(section, option)
+	if err != nil || p.options == nil {
+		return value, err
+	}
+
+	if p.options.Interpolate {
+		value, err = p.interpolateOption(section, option, value, nil)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return p.resolveSecret(section, option, value)
+}
+
+// GetRaw returns string value for the named option, without interpolating
+// it.
+func (p *ConfigParser) GetRaw(section, option string) (string, error) {
 	if section == "" || option == "" {
 		return "", errors.New("section and option must be non-empty")
 	}
@@ -150,6 +179,45 @@ func (p *ConfigParser) Items(section string) (Dict, error) {
 	return p.config[section].Items(), nil
 }
 
+// AddSection adds a new, empty section to the configuration.
+//
+// Returns an error if the section already exists or is the DEFAULT section.
+func (p *ConfigParser) AddSection(section string) error {
+	if section == "" || p.isDefaultSection(section) {
+		return fmt.Errorf("invalid section name: '%s'", section)
+	}
+
+	if p.HasSection(section) {
+		return fmt.Errorf("section already exists: '%s'", section)
+	}
+
+	p.getOrAddSection(section)
+	return nil
+}
+
+// getOrAddSection returns the named section (or the defaults, for the DEFAULT
+// section), adding it to the configuration if it doesn't exist yet.
+func (p *ConfigParser) getOrAddSection(section string) *Section {
+	if p.isDefaultSection(section) {
+		return p.defaults
+	}
+
+	if current, present := p.config[section]; present {
+		return current
+	}
+
+	current := newSection(section)
+	p.config[section] = current
+	p.sectionOrder = append(p.sectionOrder, section)
+	return current
+}
+
+// OrderedSections returns a list of section names, excluding [DEFAULT], in the
+// order they appeared in the source or were added.
+func (p *ConfigParser) OrderedSections() []string {
+	return append([]string(nil), p.sectionOrder...)
+}
+
 // Set puts the given option into the named section.
 //
 // Returns an error if the section does not exist.
@@ -258,42 +326,70 @@ func (p *ConfigParser) GetStringSlice(section, option string) ([]string, error)
 		return nil, err
 	}
 
-	return parseToStringArray(result), nil
+	return parseToStringArray(result, p.hasQuotedValues()), nil
 }
 
-// getArrayValueToSet returns array value to set.
+// getArrayValueToSet returns array value to set. defaultValue is used when
+// neither the config file nor the environment has a value.
 func (p *ConfigParser) getArrayValueToSet(
-	section, key, envKey string,
-	kind reflect.Kind,
+	section, key, envKey, defaultValue string,
+	arrayType reflect.Type,
 ) (rValue, error) {
+	p.addKnownKey(section, key)
+
+	// sourceEnv is the environment variable the value comes from, if any.
+	sourceEnv := ""
+	sourceKind := SourceFile
 	result, err := p.Get(section, key)
-	if err != nil || result == "" {
+	if p.reportValueError(section, key, err) {
+		return invalidReflectValue, err
+	} else if err != nil || result == "" {
+		sourceKind = SourceEnv
 		// second try: read from environment variable
-		var envTries []string
-
-		if envKey != "" {
-			envTries = append(envTries, envKey)
-		}
-		envTries = append(envTries, strings.ToUpper(section)+"_"+strings.ToUpper(key))
-		envTries = append(envTries, key)
-		envTries = append(envTries, strings.ToUpper(key))
-
-		for _, envTry := range envTries {
-			result = os.Getenv(envTry)
-			if result != "" {
+		for _, envTry := range getArrayEnvTries(envKey, section, key) {
+			result, err = p.getEnv(section, key, envTry)
+			if p.reportValueError(section, key, err) {
+				return invalidReflectValue, err
+			} else if result != "" {
+				sourceEnv = envTry
 				break
 			}
 		}
 	}
 
 	if result == "" {
-		// TODO: Add support for default values in arrays
+		result = defaultValue
+		sourceKind = SourceDefault
+	} else if p.options.Strict {
+		p.checkArrayValue(section, key, sourceEnv, result, arrayType)
+	}
+
+	if result == "" {
 		return invalidReflectValue, errors.New("getArrayValueToSet: no value found")
 	}
 
+	p.addValueSource(section, key, sourceKind, sourceEnv, result)
+	return parseArrayValue(result, arrayType, p.hasQuotedValues())
+}
+
+// hasQuotedValues returns true if the QuotedValues option is set.
+func (p *ConfigParser) hasQuotedValues() bool {
+	return p.options != nil && p.options.QuotedValues
+}
+
+// parseArrayValue converts result, a list of values, to a value of arrayType;
+// quotedValues is passed to parseToStringArray.
+func parseArrayValue(result string, arrayType reflect.Type, quotedValues bool) (rValue, error) {
+	if arrayType.Kind() == reflect.Slice {
+		if decode := getDecoder(arrayType.Elem()); decode != nil {
+			return parseToDecodedArray(result, arrayType, decode, quotedValues), nil
+		}
+	}
+
+	kind := getArrayKind(arrayType)
 	switch kind {
 	case reflect.String:
-		return reflect.ValueOf(parseToStringArray(result)), nil
+		return reflect.ValueOf(parseToStringArray(result, quotedValues)), nil
 	case reflect.Int:
 		return reflect.ValueOf(rangeValues.ParseIntArray[int](result)), nil
 	case reflect.Int8:
@@ -338,6 +434,23 @@ func (p *ConfigParser) GetIntSlice(section, option string) ([]int64, error) {
 	return rangeValues.ParseIntArray[int64](result), nil
 }
 
+// hasSectionOrSubSection returns true if the named section, or any dotted
+// sub-section of it (such as "database.replica" for "database"), is present.
+func (p *ConfigParser) hasSectionOrSubSection(section string) bool {
+	if p.HasSection(section) {
+		return true
+	}
+
+	prefix := section + "."
+	for name := range p.config {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (p *ConfigParser) HasOption(section, option string) (bool, error) {
 	var s *Section
 	if p.isDefaultSection(section) {
@@ -367,11 +480,24 @@ func (p *ConfigParser) RemoveOption(section, option string) error {
 //---------------------------------------------------------
 
 func (s *Section) Add(key, value string) error {
+	s.addValue(key, s.safeValue(value))
+	delete(s.sources, s.safeKey(key))
+	return nil
+}
+
+// addValue adds the option without trimming its value, as quoted values may
+// have leading or trailing spaces.
+func (s *Section) addValue(key, value string) {
 	lookupKey := s.safeKey(key)
+	if existingKey, present := s.lookup[lookupKey]; present {
+		// option names are case-insensitive; keep the original spelling.
+		key = existingKey
+	} else {
+		s.order = append(s.order, key)
+	}
 
-	s.options[key] = s.safeValue(value)
+	s.options[key] = value
 	s.lookup[lookupKey] = key
-	return nil
 }
 
 func (s *Section) Get(key string) (string, error) {
@@ -381,7 +507,7 @@ func (s *Section) Get(key string) (string, error) {
 	}
 
 	if value, present := s.options[lookupKey]; present {
-		return strings.TrimSpace(value), nil
+		return value, nil
 	}
 
 	return "", getNoOptionError(s.Name, key)
@@ -413,9 +539,22 @@ func (s *Section) Remove(key string) error {
 	// that the passed key to be removed matches the options key.
 	delete(s.lookup, s.safeKey(key))
 	delete(s.options, key)
+	delete(s.sources, s.safeKey(key))
+	for i, current := range s.order {
+		if current == key {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
 	return nil
 }
 
+// OrderedOptions returns the option names of the section in the order they
+// were added.
+func (s *Section) OrderedOptions() []string {
+	return append([]string(nil), s.order...)
+}
+
 func newSection(name string) *Section {
 	return &Section{
 		Name:    name,
@@ -479,4 +618,266 @@ func (e *InvalidParseError) Error() string {
 	return "strongParser: Parse(nil " + e.Type.String() + ")"
 }
 
+func (e *ParseError) Error() string {
+	position := fmt.Sprintf("%d:%d", e.Line, e.Column)
+	if e.File != "" {
+		position = e.File + ":" + position
+	}
+
+	message := fmt.Sprintf("%s: %s: %s", position, e.Kind, e.Snippet)
+	if e.Err != nil {
+		message += " (" + e.Err.Error() + ")"
+	}
+
+	return message
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+func (k ParseErrorKind) String() string {
+	switch k {
+	case ParseErrorMalformedLine:
+		return "malformed line"
+	case ParseErrorMissingSection:
+		return "missing section header"
+	case ParseErrorUnterminatedSection:
+		return "unterminated section header"
+	case ParseErrorDuplicateSection:
+		return "duplicate section"
+	case ParseErrorUnterminatedValue:
+		return "unterminated triple-quoted value"
+	case ParseErrorInvalidDocument:
+		return "invalid document"
+	}
+
+	return "ParseErrorKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+func (e ParseErrors) Error() string {
+	if len(e) == 1 {
+		return "strongParser: " + e[0].Error()
+	}
+
+	lines := make([]string, 0, len(e)+1)
+	lines = append(lines, fmt.Sprintf("strongParser: %d parse errors:", len(e)))
+	for _, current := range e {
+		lines = append(lines, "\t"+current.Error())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (e ParseErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, current := range e {
+		errs = append(errs, current)
+	}
+
+	return errs
+}
+
+//---------------------------------------------------------
+
+// feed parses a single line of the configuration source. Problems are
+// recorded, so that all of them can be reported by finish.
+func (lp *lineParser) feed(current string) {
+	lp.lineNo++
+	p := lp.p
+	if lp.continuesOption(current) {
+		lp.continueOption(current)
+		return
+	}
+
+	lp.lastOption = nil
+	line := strings.TrimSpace(current)
+	rawLine := &configLine{kind: configLineRaw, raw: current, lineNo: lp.lineNo}
+
+	// Skip comment lines and empty lines
+	if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") ||
+		(lp.options.InlineComments && strings.HasPrefix(line, ";")) {
+		p.lines = append(p.lines, rawLine)
+		return
+	}
+
+	if match := sectionHeader.FindStringSubmatch(line); len(match) > 0 {
+		if _, exists := lp.sectionLines[match[1]]; exists {
+			lp.addError(ParseErrorDuplicateSection, getColumn(current), line)
+		} else {
+			lp.sectionLines[match[1]] = lp.lineNo
+		}
+
+		lp.curSect = p.getOrAddSection(match[1])
+		p.lines = append(p.lines, &configLine{
+			kind:    configLineSection,
+			raw:     current,
+			lineNo:  lp.lineNo,
+			section: lp.curSect,
+		})
+	} else if strings.HasPrefix(line, "[") {
+		lp.addError(ParseErrorUnterminatedSection, len(current)+1, line)
+		p.lines = append(p.lines, rawLine)
+	} else if match = keyValue.FindStringSubmatch(line); len(match) > 0 {
+		key := strings.TrimSpace(match[1])
+		if lp.curSect == nil {
+			p.lines = append(p.lines, rawLine)
+			if !lp.options.Includes || !strings.EqualFold(key, includeOptionName) {
+				if lp.options.Strict {
+					// reported by ParseConfig, along with the other problems.
+					lp.addViolation(key, "key is outside of any section", ErrKeyOutsideSection)
+				} else {
+					lp.addError(ParseErrorMissingSection, getColumn(current), line)
+				}
+
+				return
+			}
+
+			// "include = path" before the first section.
+			value, _, _, _ := parseOptionValue(match[3], lp.options)
+			p.includes = append(p.includes, value)
+			return
+		}
+
+		if lp.options.Strict {
+			if firstLine := p.getOptionLine(lp.curSect.Name, key); firstLine != 0 {
+				lp.addViolation(key, fmt.Sprintf("duplicate key, first defined on line %d", firstLine),
+					ErrDuplicateKey)
+			}
+		}
+
+		// the value is always at the end of the line.
+		trimmed := strings.TrimRightFunc(current, unicode.IsSpace)
+		option := &configLine{
+			kind:    configLineOption,
+			raw:     current,
+			lineNo:  lp.lineNo,
+			section: lp.curSect,
+			key:     key,
+			prefix:  trimmed[:len(trimmed)-len(match[3])],
+		}
+		p.lines = append(p.lines, option)
+
+		lp.lastOption = option
+		lp.rawValue = match[3]
+		lp.optionLine = lp.lineNo
+		lp.optionColumn = len(option.prefix) + 1
+		lp.updateOption()
+	} else if strings.HasPrefix(line, ";") {
+		// a comment, even if inline comments are disabled.
+		p.lines = append(p.lines, rawLine)
+	} else {
+		lp.addError(ParseErrorMalformedLine, getColumn(current), line)
+		p.lines = append(p.lines, rawLine)
+	}
+}
+
+// continuesOption returns true if current is a continuation line of the last
+// option.
+func (lp *lineParser) continuesOption(current string) bool {
+	if lp.lastOption == nil {
+		return false
+	}
+
+	if lp.valueOpen {
+		return true
+	}
+
+	return lp.options.MultilineValues &&
+		strings.TrimSpace(current) != "" &&
+		unicode.IsSpace(rune(current[0]))
+}
+
+// continueOption appends current to the value of the last option.
+func (lp *lineParser) continueOption(current string) {
+	lp.lastOption.raw += "\n" + current
+	lp.rawValue += "\n" + current
+	lp.updateOption()
+}
+
+// updateOption sets the value of the last option from its raw value.
+func (lp *lineParser) updateOption() {
+	option := lp.lastOption
+	value, comment, quoted, open := parseOptionValue(lp.rawValue, lp.options)
+	option.value = value
+	option.comment = comment
+	option.section.addValue(option.key, value)
+	option.section.setSource(option.key, &ValueSource{
+		Kind: SourceFile,
+		File: lp.filename,
+		Line: option.lineNo,
+	})
+
+	lp.valueOpen = open
+	lp.valueQuoted = quoted
+	if quoted && !open {
+		// a complete quoted value can't be continued.
+		lp.lastOption = nil
+	}
+}
+
+// addViolation records a problem of the current line, found by the Strict
+// option.
+func (lp *lineParser) addViolation(key, reason string, err error) {
+	section := ""
+	if lp.curSect != nil {
+		section = lp.curSect.Name
+	}
+
+	lp.p.sourceViolations = append(lp.p.sourceViolations, &ValidationError{
+		Section: section,
+		Key:     key,
+		Reason:  reason,
+		Err:     err,
+		Line:    lp.lineNo,
+	})
+}
+
+// addError records a problem of the current line.
+func (lp *lineParser) addError(kind ParseErrorKind, column int, snippet string) {
+	lp.errs = append(lp.errs, &ParseError{
+		File:    lp.filename,
+		Line:    lp.lineNo,
+		Column:  column,
+		Snippet: snippet,
+		Kind:    kind,
+	})
+}
+
+// finish checks the state at the end of the source, and returns the parsed
+// configuration, or all of the problems found in the source.
+func (lp *lineParser) finish() (*ConfigParser, error) {
+	if lp.lastOption != nil && lp.valueOpen && lp.valueQuoted {
+		lp.errs = append(lp.errs, &ParseError{
+			File:    lp.filename,
+			Line:    lp.optionLine,
+			Column:  lp.optionColumn,
+			Snippet: strings.TrimSpace(strings.SplitN(lp.lastOption.raw, "\n", 2)[0]),
+			Kind:    ParseErrorUnterminatedValue,
+		})
+	}
+
+	if len(lp.errs) != 0 {
+		return nil, lp.errs
+	}
+
+	p := lp.p
+	if includeSection := p.config[includeSectionName]; includeSection != nil && lp.options.Includes {
+		// the [include] section is a directive, not a part of the config.
+		for _, key := range includeSection.order {
+			p.includes = append(p.includes, includeSection.options[key])
+		}
+
+		delete(p.config, includeSectionName)
+		for i, name := range p.sectionOrder {
+			if name == includeSectionName {
+				p.sectionOrder = append(p.sectionOrder[:i], p.sectionOrder[i+1:]...)
+				break
+			}
+		}
+	}
+
+	return p, nil
+}
+
 //---------------------------------------------------------
@@ -0,0 +1,56 @@
+package strongParser
+
+import "fmt"
+
+// getEnv returns the value of the named environment variable for option in
+// section. With the ResolveSecrets option, the variable with a "_FILE"
+// suffix is used if it's not set, and secret values are resolved.
+func (p *ConfigParser) getEnv(section, option, name string) (string, error) {
+	value := p.lookupEnv(name)
+	if p.options == nil || !p.options.ResolveSecrets {
+		return value, nil
+	}
+
+	if value == "" {
+		path := p.lookupEnv(name + secretFileEnvSuffix)
+		if path == "" {
+			return "", nil
+		}
+
+		content, err := readSecretFile(path)
+		if err != nil {
+			return "", &SecretError{
+				Section: section,
+				Option:  option,
+				Err: fmt.Errorf("environment variable %s: %w",
+					p.options.EnvPrefix+name+secretFileEnvSuffix, err),
+			}
+		}
+
+		return content, nil
+	}
+
+	return p.resolveSecret(section, option, value)
+}
+
+// lookupEnv returns the value of the named environment variable, using the
+// EnvPrefix and DotEnvFiles options.
+func (p *ConfigParser) lookupEnv(name string) string {
+	return lookupEnv(p.options, p.dotEnv, name)
+}
+
+// loadDotEnv loads the variables of the DotEnvFiles option, unless they are
+// loaded already.
+func (p *ConfigParser) loadDotEnv() error {
+	if p.dotEnv != nil {
+		return nil
+	}
+
+	dotEnv, err := loadDotEnvFiles(p.options)
+	if err != nil {
+		return err
+	}
+
+	p.dotEnv = dotEnv
+	return nil
+}
@@ -0,0 +1,34 @@
+package strongParser
+
+import (
+	"reflect"
+	"strings"
+)
+
+// String returns the value given to the flag.
+func (f *fieldFlag) String() string {
+	if f == nil {
+		return ""
+	}
+
+	return f.value
+}
+
+// Set checks that value can be converted to the type of the field, and
+// records it.
+func (f *fieldFlag) Set(value string) error {
+	fType := strings.ToLower(f.info.field.Tag.Get("type"))
+	if _, err := convertString(f.info.valueType, fType, value); err != nil {
+		return err
+	}
+
+	f.value = value
+	f.isSet = true
+	return nil
+}
+
+// IsBoolFlag returns true for the flags of bool fields, which may be given
+// without a value.
+func (f *fieldFlag) IsBoolFlag() bool {
+	return f.info.valueType.Kind() == reflect.Bool && getDecoder(f.info.valueType) == nil
+}
@@ -0,0 +1,144 @@
+package strongParser
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Parse reads an INI source from r, line by line.
+func (iniFormat) Parse(r io.Reader, filename string, opt *ConfigParserOptions) (*ConfigParser, error) {
+	lp := newLineParser(opt)
+	lp.filename = filename
+
+	reader := bufio.NewReader(r)
+	for {
+		l, err := reader.ReadString('\n')
+		if l == "" && err != nil {
+			if err != io.EOF {
+				return nil, err
+			}
+
+			break
+		}
+
+		lp.feed(strings.TrimSuffix(strings.TrimSuffix(l, "\n"), "\r"))
+	}
+
+	return lp.finish()
+}
+
+//---------------------------------------------------------
+
+// Parse reads a JSON source from r; its content must be a single object.
+func (jsonFormat) Parse(r io.Reader, filename string, opt *ConfigParserOptions) (*ConfigParser, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	p := NewConfigParser()
+	p.options = opt
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	token, err := decoder.Token()
+	if err == nil && token != json.Delim('{') {
+		err = errors.New("the content must be an object")
+	}
+
+	if err == nil {
+		err = readJSONSection(decoder, "", getMainSectionName(opt), func(section, key, value string) {
+			current := p.getOrAddSection(section)
+			if key == "" {
+				return
+			}
+
+			current.addValue(key, value)
+
+			// the value is the last token read.
+			offset := max(0, min(decoder.InputOffset()-1, int64(len(data))))
+			current.setSource(key, &ValueSource{
+				Kind: SourceFile,
+				File: filename,
+				Line: bytes.Count(data[:offset], []byte{'\n'}) + 1,
+			})
+		})
+	}
+
+	if err == nil {
+		if _, err = decoder.Token(); err == io.EOF {
+			return p, nil
+		} else if err == nil {
+			err = errors.New("unexpected content after the object")
+		}
+	}
+
+	return nil, getJSONParseError(filename, data, decoder.InputOffset(), err)
+}
+
+//---------------------------------------------------------
+
+// Parse reads a .env source from r.
+func (dotEnvFormat) Parse(r io.Reader, filename string, opt *ConfigParserOptions) (*ConfigParser, error) {
+	p := NewConfigParser()
+	p.options = opt
+	p.flatKeys = true
+
+	mainSection := getMainSectionName(opt)
+	err := readDotEnv(r, filename, func(name, value string, lineNo int) {
+		section, key := mainSection, name
+		if index := strings.LastIndexByte(name, '.'); index > 0 && index < len(name)-1 {
+			section, key = name[:index], name[index+1:]
+		}
+
+		current := p.getOrAddSection(section)
+		current.addValue(key, value)
+		current.setSource(key, &ValueSource{Kind: SourceFile, File: filename, Line: lineNo})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+//---------------------------------------------------------
+
+// mapFlatKeys moves the options of the main section that are named like the
+// environment variable of a field of t in another section (such as
+// DATABASE_URL for the url key of [database], see getEnvTries) to the
+// section of that field, unless it already has the option. section and
+// parentSection have the same meaning as in parseStruct.
+func (p *ConfigParser) mapFlatKeys(t reflect.Type, section, parentSection string) {
+	mainSection := getMainSectionName(p.options)
+	main := p.config[mainSection]
+	if main == nil {
+		return
+	}
+
+	walkFields(t, section, parentSection, "", mainSection, func(info *fieldInfo) {
+		if info.kind != fieldValue || info.section == mainSection {
+			return
+		}
+
+		name, present := main.lookup[main.safeKey(getEnvSectionName(info.section)+"_"+info.key)]
+		if !present {
+			return
+		}
+
+		target := p.getOrAddSection(info.section)
+		if _, err := target.Get(info.key); err == nil {
+			return
+		}
+
+		target.addValue(info.key, main.options[name])
+		target.setSource(info.key, main.getSource(name))
+		_ = main.Remove(name)
+	})
+}
@@ -0,0 +1,206 @@
+package strongParser
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// load parses the named file, and merges the files it includes into it.
+func (l *includeLoader) load(path string) (*ConfigParser, error) {
+	absPath, err := l.getAbsPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, current := range l.visiting {
+		if current == absPath {
+			chain := strings.Join(append(l.visiting[i:], absPath), " -> ")
+			return nil, fmt.Errorf("%w: %s", ErrIncludeCycle, chain)
+		}
+	}
+
+	file, err := l.open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	l.files = append(l.files, path)
+
+	p, err := getFormat(path, l.options).Parse(file, path, l.options)
+	_ = file.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	p.dotEnv = l.dotEnv
+
+	l.visiting = append(l.visiting, absPath)
+	defer func() {
+		l.visiting = l.visiting[:len(l.visiting)-1]
+	}()
+
+	err = l.resolveIncludes(p, l.getDir(absPath))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return p, nil
+}
+
+// resolveIncludes loads the files included by p, in order, and merges them
+// into it; dir is the directory relative paths are resolved from.
+func (l *includeLoader) resolveIncludes(p *ConfigParser, dir string) error {
+	if len(p.includes) == 0 {
+		return nil
+	}
+
+	base := NewConfigParser()
+	for _, current := range p.includes {
+		current = l.joinPath(dir, current)
+
+		included, err := l.load(current)
+		if err != nil {
+			return err
+		}
+
+		base.override(included)
+	}
+
+	p.inherit(base)
+	return nil
+}
+
+// getProfile returns the name of the profile overlay to load, if any.
+func (l *includeLoader) getProfile() string {
+	if l.options.Profile != "" {
+		return l.options.Profile
+	}
+
+	if l.options.ProfileEnv == "" {
+		return ""
+	}
+
+	return strings.TrimSpace(lookupEnv(l.options, l.dotEnv, l.options.ProfileEnv))
+}
+
+// open opens the named file of the file system of the loader.
+func (l *includeLoader) open(name string) (io.ReadCloser, error) {
+	if l.fsys != nil {
+		return l.fsys.Open(name)
+	}
+
+	return os.Open(name)
+}
+
+// stat returns the information of the named file of the file system of the
+// loader.
+func (l *includeLoader) stat(name string) (fs.FileInfo, error) {
+	if l.fsys != nil {
+		return fs.Stat(l.fsys, name)
+	}
+
+	return os.Stat(name)
+}
+
+func (l *includeLoader) fileExists(name string) bool {
+	info, err := l.stat(name)
+	return err == nil && !info.IsDir()
+}
+
+// getAbsPath returns the absolute path of the named file, which identifies
+// it when detecting include cycles; the names of an fs.FS are always
+// absolute.
+func (l *includeLoader) getAbsPath(name string) (string, error) {
+	if l.fsys != nil {
+		return path.Clean(name), nil
+	}
+
+	return filepath.Abs(name)
+}
+
+// getDir returns the directory of the named file.
+func (l *includeLoader) getDir(name string) string {
+	if l.fsys != nil {
+		return path.Dir(name)
+	}
+
+	return filepath.Dir(name)
+}
+
+// joinPath resolves the path of an included file from dir, unless it's
+// absolute.
+func (l *includeLoader) joinPath(dir, name string) string {
+	if l.fsys != nil {
+		return path.Join(dir, name)
+	}
+
+	if filepath.IsAbs(name) {
+		return name
+	}
+
+	return filepath.Join(dir, name)
+}
+
+//---------------------------------------------------------
+
+// override sets all of the options of src in p, replacing existing values.
+func (p *ConfigParser) override(src *ConfigParser) {
+	overrideSection := func(section, srcSection *Section) {
+		for _, key := range srcSection.order {
+			section.addValue(key, srcSection.options[key])
+			section.setSource(key, srcSection.getSource(key))
+		}
+	}
+
+	overrideSection(p.defaults, src.defaults)
+	for _, name := range src.sectionOrder {
+		overrideSection(p.getOrAddSection(name), src.config[name])
+	}
+
+	p.sourceViolations = append(p.sourceViolations, src.sourceViolations...)
+}
+
+// inherit adds the options of base that are not in p to it, and records
+// them as inherited.
+func (p *ConfigParser) inherit(base *ConfigParser) {
+	if p.inherited == nil {
+		p.inherited = make(map[string]Dict)
+	}
+
+	inheritSection := func(section, baseSection *Section) {
+		for _, key := range baseSection.order {
+			if _, err := section.Get(key); err == nil {
+				continue
+			}
+
+			value := baseSection.options[key]
+			section.addValue(key, value)
+			section.setSource(key, baseSection.getSource(key))
+			if p.inherited[section.Name] == nil {
+				p.inherited[section.Name] = make(Dict)
+			}
+
+			p.inherited[section.Name][section.safeKey(key)] = value
+		}
+	}
+
+	inheritSection(p.defaults, base.defaults)
+	for _, name := range base.sectionOrder {
+		inheritSection(p.getOrAddSection(name), base.config[name])
+	}
+
+	// the problems of the base come first, as it's loaded first.
+	p.sourceViolations = append(base.sourceViolations, p.sourceViolations...)
+}
+
+// isInherited returns true if the option has the value it was inherited
+// with from an included file or a lower layer.
+func (p *ConfigParser) isInherited(section *Section, key string) bool {
+	value, present := p.inherited[section.Name][section.safeKey(key)]
+	return present && value == section.options[key]
+}
@@ -0,0 +1,188 @@
+package strongParser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// interpolateOption expands the references in value, the raw value of option
+// in section. visiting holds the options that are being expanded, in order,
+// as "section:option"; it's used to detect cycles.
+func (p *ConfigParser) interpolateOption(section, option, value string, visiting []string) (string, error) {
+	id := section + ":" + strings.ToLower(option)
+	for i, current := range visiting {
+		if current == id {
+			chain := strings.Join(append(visiting[i:], id), " -> ")
+			return "", &InterpolationError{
+				Section: section,
+				Option:  option,
+				Err:     fmt.Errorf("%w: %s", ErrInterpolationCycle, chain),
+			}
+		}
+	}
+
+	result, err := p.interpolate(section, value, append(visiting, id))
+	if err != nil {
+		if _, ok := err.(*InterpolationError); ok {
+			return "", err
+		}
+
+		return "", &InterpolationError{
+			Section: section,
+			Option:  option,
+			Err:     err,
+		}
+	}
+
+	return result, nil
+}
+
+// interpolate expands the references in value, which belongs to section.
+func (p *ConfigParser) interpolate(section, value string, visiting []string) (string, error) {
+	if !strings.ContainsAny(value, "$%") {
+		return value, nil
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if (c != '$' && c != '%') || i+1 == len(value) {
+			sb.WriteByte(c)
+			continue
+		}
+
+		next := value[i+1]
+		switch {
+		case next == c:
+			// escaped: $$ or %%
+			sb.WriteByte(c)
+			i++
+		case c == '$' && next == '{':
+			end := findClosingBrace(value, i+2)
+			if end == -1 {
+				return "", fmt.Errorf("%w: unterminated ${ in %q", ErrInvalidInterpolation, value)
+			}
+
+			expanded, err := p.expandReference(section, value[i+2:end], visiting)
+			if err != nil {
+				return "", err
+			}
+
+			sb.WriteString(expanded)
+			i = end
+		case c == '%' && next == '(':
+			end := strings.Index(value[i+2:], ")s")
+			if end == -1 {
+				return "", fmt.Errorf("%w: unterminated %%( in %q", ErrInvalidInterpolation, value)
+			}
+
+			end += i + 2
+			expanded, err := p.expandOption(section, value[i+2:end], visiting)
+			if err != nil {
+				return "", err
+			}
+
+			sb.WriteString(expanded)
+			i = end + 1
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	return sb.String(), nil
+}
+
+// expandReference expands the content of a ${...} reference: either
+// "section:key", "ENV_VAR" or "ENV_VAR:-fallback".
+func (p *ConfigParser) expandReference(section, reference string, visiting []string) (string, error) {
+	if name, fallback, ok := strings.Cut(reference, ":-"); ok {
+		if envValue := p.lookupEnv(strings.TrimSpace(name)); envValue != "" {
+			return envValue, nil
+		}
+
+		// the fallback may contain references too.
+		return p.interpolate(section, fallback, visiting)
+	}
+
+	if refSection, key, ok := strings.Cut(reference, ":"); ok {
+		refSection = strings.TrimSpace(refSection)
+		if refSection == "" {
+			refSection = section
+		}
+
+		return p.expandOption(refSection, key, visiting)
+	}
+
+	return p.lookupEnv(strings.TrimSpace(reference)), nil
+}
+
+// expandOption returns the interpolated value of option in section.
+func (p *ConfigParser) expandOption(section, option string, visiting []string) (string, error) {
+	option = strings.TrimSpace(option)
+	if option == "" {
+		return "", fmt.Errorf("%w: empty option name", ErrInvalidInterpolation)
+	}
+
+	value, err := p.GetRaw(section, option)
+	if err != nil {
+		return "", err
+	}
+
+	return p.interpolateOption(section, option, value, visiting)
+}
+
+// reportValueError adds err to the violations of the struct being parsed if
+// it's an *InterpolationError or a *SecretError, and returns true in that
+// case.
+func (p *ConfigParser) reportValueError(section, key string, err error) bool {
+	var interpolationErr *InterpolationError
+	var secretErr *SecretError
+	var reason string
+	switch {
+	case errors.As(err, &interpolationErr):
+		reason = interpolationErr.Err.Error()
+	case errors.As(err, &secretErr):
+		reason = secretErr.Err.Error()
+	default:
+		return false
+	}
+
+	p.violations = append(p.violations, &ValidationError{
+		Section: section,
+		Key:     key,
+		Reason:  reason,
+		Err:     err,
+	})
+	return true
+}
+
+// findClosingBrace returns the index of the '}' closing a '{' that ends just
+// before start, taking nested braces into account; or -1 if there is none.
+func findClosingBrace(value string, start int) int {
+	depth := 1
+	for i := start; i < len(value); i++ {
+		switch value[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+//---------------------------------------------------------
+
+func (e *InterpolationError) Error() string {
+	return fmt.Sprintf("strongParser: failed to interpolate option '%s' in section '%s': %v",
+		e.Option, e.Section, e.Err)
+}
+
+func (e *InterpolationError) Unwrap() error {
+	return e.Err
+}
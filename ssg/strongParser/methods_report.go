@@ -0,0 +1,130 @@
+package strongParser
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Explain returns a description of the report, with a line for each field
+// in the form of "Path = value (source)", sorted by path. The values of
+// secret fields are masked.
+func (r ConfigReport) Explain() string {
+	paths := make([]string, 0, len(r))
+	for path := range r {
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+
+	var sb strings.Builder
+	for _, path := range paths {
+		source := r[path]
+		value := maskValue(source.Value, source.secret)
+		sb.WriteString(path + " = " + strconv.Quote(value) + " (" + source.String() + ")\n")
+	}
+
+	return sb.String()
+}
+
+// String returns a short description of the source, such as
+// "config.ini:12", "env MAIN_TOKEN", "default" or "flag --main.port".
+func (s *ValueSource) String() string {
+	switch s.Kind {
+	case SourceFile:
+		switch {
+		case s.File == "" && s.Line == 0:
+			return "config"
+		case s.File == "":
+			return "line " + strconv.Itoa(s.Line)
+		case s.Line == 0:
+			return s.File
+		}
+
+		return s.File + ":" + strconv.Itoa(s.Line)
+	case SourceEnv:
+		return "env " + s.Env
+	case SourceFlag:
+		return "flag " + s.Flag
+	}
+
+	return s.Kind.String()
+}
+
+func (k SourceKind) String() string {
+	switch k {
+	case SourceFile:
+		return "file"
+	case SourceEnv:
+		return "env"
+	case SourceDefault:
+		return "default"
+	case SourceFlag:
+		return "flag"
+	}
+
+	return "SourceKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+//---------------------------------------------------------
+
+// addValueSource records where value, the value of the option key in
+// section, came from if a report is requested: SourceFile for the option of
+// the config (whose source may be a flag), SourceEnv for the environment
+// variable env, or SourceDefault.
+func (p *ConfigParser) addValueSource(section, key string, kind SourceKind, env, value string) {
+	if p.valueSources == nil {
+		return
+	}
+
+	var source *ValueSource
+	switch kind {
+	case SourceFile:
+		source = p.getOptionSource(section, key)
+	case SourceEnv:
+		source = &ValueSource{Kind: SourceEnv, Env: p.options.EnvPrefix + env}
+	default:
+		source = &ValueSource{Kind: kind}
+	}
+
+	source.Value = value
+
+	if p.valueSources[section] == nil {
+		p.valueSources[section] = make(map[string]*ValueSource)
+	}
+
+	p.valueSources[section][strings.ToLower(key)] = source
+}
+
+// getOptionSource returns the source of the value that Get returns for the
+// option key in section, as a new value.
+func (p *ConfigParser) getOptionSource(section, key string) *ValueSource {
+	if current := p.config[section]; current != nil && !p.isDefaultSection(section) {
+		if _, err := current.Get(key); err == nil {
+			return getSourceCopy(current.getSource(key), SourceFile)
+		}
+	}
+
+	return getSourceCopy(p.defaults.getSource(key), SourceFile)
+}
+
+//---------------------------------------------------------
+
+// setSource sets the source of the option key; a nil source removes it.
+func (s *Section) setSource(key string, source *ValueSource) {
+	if source == nil {
+		delete(s.sources, s.safeKey(key))
+		return
+	}
+
+	if s.sources == nil {
+		s.sources = make(map[string]*ValueSource)
+	}
+
+	s.sources[s.safeKey(key)] = source
+}
+
+// getSource returns the source of the option key, or nil if it has none.
+func (s *Section) getSource(key string) *ValueSource {
+	return s.sources[s.safeKey(key)]
+}
@@ -0,0 +1,75 @@
+package strongParser
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// resolveSecret returns the actual value of a "file:" or "enc:" value of
+// option in section, if secrets are enabled; other values are returned as
+// they are.
+func (p *ConfigParser) resolveSecret(section, option, value string) (string, error) {
+	if p.options == nil || !p.options.ResolveSecrets {
+		return value, nil
+	}
+
+	var err error
+	switch {
+	case strings.HasPrefix(value, secretFilePrefix):
+		value, err = readSecretFile(strings.TrimPrefix(value, secretFilePrefix))
+	case strings.HasPrefix(value, encryptedValuePrefix):
+		var key []byte
+		key, err = p.getSecretKey()
+		if err == nil {
+			value, err = DecryptValue(value, key)
+		}
+	default:
+		return value, nil
+	}
+
+	if err != nil {
+		return "", &SecretError{
+			Section: section,
+			Option:  option,
+			Err:     err,
+		}
+	}
+
+	return value, nil
+}
+
+// getSecretKey returns the key of encrypted values.
+func (p *ConfigParser) getSecretKey() ([]byte, error) {
+	if len(p.options.SecretKey) != 0 {
+		return p.options.SecretKey, nil
+	}
+
+	envName := p.options.SecretKeyEnv
+	if envName == "" {
+		envName = DefaultSecretKeyEnv
+	}
+
+	encodedKey := strings.TrimSpace(p.lookupEnv(envName))
+	if encodedKey == "" {
+		return nil, ErrNoSecretKey
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s is not valid base64", ErrInvalidSecretKey, envName)
+	}
+
+	return key, nil
+}
+
+//---------------------------------------------------------
+
+func (e *SecretError) Error() string {
+	return fmt.Sprintf("strongParser: secret value of '%s' in section '%s': %v",
+		e.Option, e.Section, e.Err)
+}
+
+func (e *SecretError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,158 @@
+package strongParser
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// addKnownKey records that key of section is mapped to a field, for the
+// Strict option.
+func (p *ConfigParser) addKnownKey(section, key string) {
+	if !p.options.Strict {
+		return
+	}
+
+	if p.knownKeys == nil {
+		p.knownKeys = make(map[string]map[string]bool)
+	}
+
+	keys, present := p.knownKeys[section]
+	if present && keys == nil {
+		// every key of the section is known already.
+		return
+	}
+
+	if keys == nil {
+		keys = make(map[string]bool)
+		p.knownKeys[section] = keys
+	}
+
+	keys[strings.ToLower(key)] = true
+}
+
+// addKnownSection records that every key of section is mapped to a field
+// (such as a map field), for the Strict option.
+func (p *ConfigParser) addKnownSection(section string) {
+	if !p.options.Strict {
+		return
+	}
+
+	if p.knownKeys == nil {
+		p.knownKeys = make(map[string]map[string]bool)
+	}
+
+	p.knownKeys[section] = nil
+}
+
+// checkUnknownKeys adds a violation for each key of the sections mapped to
+// the parsed struct that doesn't match any of its fields.
+func (p *ConfigParser) checkUnknownKeys() {
+	if !p.options.Strict {
+		return
+	}
+
+	for _, name := range p.sectionOrder {
+		keys, present := p.knownKeys[name]
+		section := p.config[name]
+		if !present || keys == nil || section == nil {
+			continue
+		}
+
+		for _, key := range section.order {
+			if keys[strings.ToLower(key)] {
+				continue
+			}
+
+			p.violations = append(p.violations, &ValidationError{
+				Section:    name,
+				Key:        key,
+				Reason:     "unknown key",
+				Err:        ErrUnknownKey,
+				Line:       p.getOptionLine(name, key),
+				Suggestion: getSuggestion(strings.ToLower(key), keys),
+			})
+		}
+	}
+}
+
+// addTypeMismatch adds a violation for a value of the config file that
+// can't be converted to the type of its field.
+func (p *ConfigParser) addTypeMismatch(section, key, value string, err error) {
+	p.violations = append(p.violations, &ValidationError{
+		Section: section,
+		Key:     key,
+		Reason:  fmt.Sprintf("invalid value %q: %v", value, err),
+		Err:     ErrTypeMismatch,
+		Line:    p.getOptionLine(section, key),
+	})
+}
+
+// addEnvTypeMismatch adds a violation for the value of an environment
+// variable that can't be converted to the type of its field.
+func (p *ConfigParser) addEnvTypeMismatch(section, key, envKey, value string, err error) {
+	p.violations = append(p.violations, &ValidationError{
+		Section: section,
+		Key:     key,
+		Reason: fmt.Sprintf("invalid value %q of environment variable %s: %v",
+			value, p.options.EnvPrefix+envKey, err),
+		Err: ErrTypeMismatch,
+	})
+}
+
+// checkArrayValue adds a violation if any element of value, the value of a
+// slice field, can't be converted to the element type of arrayType; such
+// elements are skipped otherwise. envKey is the environment variable the
+// value comes from, if it doesn't come from the config file.
+func (p *ConfigParser) checkArrayValue(section, key, envKey, value string, arrayType reflect.Type) {
+	if arrayType.Kind() != reflect.Slice {
+		return
+	}
+
+	for _, element := range parseToStringArray(value, p.hasQuotedValues()) {
+		if element == "" {
+			continue
+		}
+
+		_, err := convertString(arrayType.Elem(), "", element)
+		if err == nil {
+			continue
+		}
+
+		if envKey == "" {
+			p.addTypeMismatch(section, key, element, err)
+		} else {
+			p.addEnvTypeMismatch(section, key, envKey, element, err)
+		}
+
+		return
+	}
+}
+
+// getOptionLine returns the number of the line that sets the option in the
+// source, or 0 if there is none (e.g. if the option is inherited from an
+// included file).
+func (p *ConfigParser) getOptionLine(section, key string) int {
+	key = strings.ToLower(strings.TrimSpace(key))
+	for i := len(p.lines) - 1; i >= 0; i-- {
+		line := p.lines[i]
+		if line.kind == configLineOption && line.section.Name == section &&
+			strings.ToLower(line.key) == key {
+			return line.lineNo
+		}
+	}
+
+	return 0
+}
+
+// getViolations returns the problems of the source found by the Strict
+// option, followed by the violations of the last parsed struct.
+func (p *ConfigParser) getViolations() ValidationErrors {
+	if len(p.sourceViolations) == 0 {
+		return p.violations
+	}
+
+	violations := make(ValidationErrors, 0, len(p.sourceViolations)+len(p.violations))
+	violations = append(violations, p.sourceViolations...)
+	return append(violations, p.violations...)
+}
@@ -0,0 +1,409 @@
+package strongParser
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ALiwoto/ssg/ssg/rangeValues"
+	"github.com/ALiwoto/ssg/ssg/timeUtils"
+)
+
+// validateField checks the validation tags of a parsed field:
+//   - `required:"true"`: the value (or the section of a nested struct) must
+//     be present;
+//   - `min`, `max` and `range:"min-max"`: bounds of numbers and durations, or
+//     of the length of strings and slices;
+//   - `oneof:"a b c"`: allowed values;
+//   - `regex`: a pattern that string values must match;
+//   - `nonempty:"true"`: slices and maps must have at least one element;
+//   - `url:"true"` and `path_exists:"true"`: values must be absolute URLs or
+//     existing paths.
+//
+// Checks of slice fields apply to each of their elements, except for the
+// length ones. Missing values are only checked by `required`.
+func (p *ConfigParser) validateField(
+	currentField reflect.Value,
+	fByName reflect.StructField,
+	section, parentSection string,
+	found bool,
+) {
+	tag := fByName.Tag
+	if isStructType(currentField.Type()) {
+		if !found && isTrueTag(tag.Get("required")) {
+			nestedSection := getNestedSectionName(fByName, parentSection)
+			p.addViolation(nestedSection, "", "section is required")
+		}
+
+		return
+	}
+
+	if currentField.Kind() == reflect.Map {
+		// maps are filled from whole sections.
+		mapSection := getNestedSectionName(fByName, parentSection)
+		if !found && isTrueTag(tag.Get("required")) {
+			p.addViolation(mapSection, "", "section is required")
+		} else if isTrueTag(tag.Get("nonempty")) && currentField.Len() == 0 {
+			p.addViolation(mapSection, "", "must not be empty")
+		}
+
+		return
+	}
+
+	fieldSection, key := p.getFieldLocation(fByName, section)
+	if !found && currentField.IsZero() {
+		if isTrueTag(tag.Get("required")) {
+			p.addViolation(fieldSection, key, "is required")
+		} else if isTrueTag(tag.Get("nonempty")) {
+			p.addViolation(fieldSection, key, "must not be empty")
+		}
+
+		return
+	}
+
+	if currentField.Kind() == reflect.Ptr {
+		currentField = currentField.Elem()
+	}
+
+	for _, reason := range getViolations(currentField, tag) {
+		p.addViolation(fieldSection, key, reason)
+	}
+}
+
+// validateStruct runs the Validate hook of the struct value rv, if it
+// implements Validator.
+func (p *ConfigParser) validateStruct(rv reflect.Value, section string) {
+	if !rv.CanAddr() {
+		return
+	}
+
+	validator, ok := rv.Addr().Interface().(Validator)
+	if !ok {
+		return
+	}
+
+	if section == "" {
+		section = p.options.MainSectionName
+	}
+
+	err := validator.Validate()
+	if err == nil {
+		return
+	}
+
+	var validationErrs ValidationErrors
+	if errors.As(err, &validationErrs) {
+		for _, current := range validationErrs {
+			if current.Section == "" {
+				current.Section = section
+			}
+
+			p.violations = append(p.violations, current)
+		}
+
+		return
+	}
+
+	p.violations = append(p.violations, &ValidationError{
+		Section: section,
+		Reason:  err.Error(),
+		Err:     err,
+	})
+}
+
+func (p *ConfigParser) addViolation(section, key, reason string) {
+	p.violations = append(p.violations, &ValidationError{
+		Section: section,
+		Key:     key,
+		Reason:  reason,
+	})
+}
+
+// getViolations returns the reasons v fails the validation tags in tag.
+func getViolations(v reflect.Value, tag reflect.StructTag) []string {
+	var reasons []string
+
+	if isTrueTag(tag.Get("nonempty")) && hasLength(v) && v.Len() == 0 {
+		reasons = append(reasons, "must not be empty")
+	}
+
+	if bound, ok := tag.Lookup("min"); ok {
+		cmp, isLength, err := compareToBound(v, bound)
+		if err != nil {
+			reasons = append(reasons, fmt.Sprintf("invalid min tag %q: %v", bound, err))
+		} else if cmp < 0 {
+			reasons = append(reasons, lengthPrefix(isLength)+"must be at least "+bound)
+		}
+	}
+
+	if bound, ok := tag.Lookup("max"); ok {
+		cmp, isLength, err := compareToBound(v, bound)
+		if err != nil {
+			reasons = append(reasons, fmt.Sprintf("invalid max tag %q: %v", bound, err))
+		} else if cmp > 0 {
+			reasons = append(reasons, lengthPrefix(isLength)+"must be at most "+bound)
+		}
+	}
+
+	if valueRange, ok := tag.Lookup("range"); ok {
+		inRange, isLength, err := isInRange(v, valueRange)
+		if err != nil {
+			reasons = append(reasons, fmt.Sprintf("invalid range tag %q: %v", valueRange, err))
+		} else if !inRange {
+			reasons = append(reasons, lengthPrefix(isLength)+"must be in range "+valueRange)
+		}
+	}
+
+	values := getCheckValues(v)
+	if oneOf, ok := tag.Lookup("oneof"); ok {
+		allowed := strings.Fields(strings.ReplaceAll(oneOf, ",", " "))
+		for _, value := range values {
+			if !containsString(allowed, value) {
+				reasons = append(reasons, fmt.Sprintf("must be one of %v, got %q", allowed, value))
+			}
+		}
+	}
+
+	if pattern, ok := tag.Lookup("regex"); ok {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			reasons = append(reasons, fmt.Sprintf("invalid regex tag %q: %v", pattern, err))
+		} else {
+			for _, value := range values {
+				if !re.MatchString(value) {
+					reasons = append(reasons, fmt.Sprintf("%q must match %s", value, pattern))
+				}
+			}
+		}
+	}
+
+	if isTrueTag(tag.Get("url")) {
+		for _, value := range values {
+			u, err := url.Parse(value)
+			if err != nil || u.Scheme == "" || u.Host == "" {
+				reasons = append(reasons, fmt.Sprintf("%q must be a valid URL", value))
+			}
+		}
+	}
+
+	if isTrueTag(tag.Get("path_exists")) {
+		for _, value := range values {
+			if _, err := os.Stat(value); err != nil {
+				reasons = append(reasons, fmt.Sprintf("path %q does not exist", value))
+			}
+		}
+	}
+
+	return reasons
+}
+
+// compareToBound compares v to bound, and returns -1, 0 or +1. Strings,
+// slices and maps are compared by their length (isLength is true).
+func compareToBound(v reflect.Value, bound string) (cmp int, isLength bool, err error) {
+	bound = strings.TrimSpace(bound)
+	if v.Type() == durationType {
+		boundValue, err := timeUtils.ParseDuration(bound)
+		return compareValues(v.Int(), int64(boundValue)), false, err
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		boundValue, err := strconv.ParseInt(bound, 10, 64)
+		return compareValues(v.Int(), boundValue), false, err
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		boundValue, err := strconv.ParseUint(bound, 10, 64)
+		return compareValues(v.Uint(), boundValue), false, err
+	case reflect.Float32, reflect.Float64:
+		boundValue, err := strconv.ParseFloat(bound, 64)
+		return compareValues(v.Float(), boundValue), false, err
+	}
+
+	if hasLength(v) {
+		boundValue, err := strconv.Atoi(bound)
+		return compareValues(getLength(v), boundValue), true, err
+	}
+
+	return 0, false, fmt.Errorf("unsupported kind: %s", v.Kind())
+}
+
+// isInRange reports whether v is in valueRange, in the form of "min-max".
+// Strings, slices and maps are checked by their length (isLength is true).
+func isInRange(v reflect.Value, valueRange string) (inRange, isLength bool, err error) {
+	errInvalid := errors.New("not a valid range")
+	if v.Type() == durationType {
+		minStr, maxStr, ok := rangeValues.SplitRange(valueRange)
+		if !ok {
+			return false, false, errInvalid
+		}
+
+		minValue, err := timeUtils.ParseDuration(minStr)
+		if err != nil {
+			return false, false, err
+		}
+
+		maxValue, err := timeUtils.ParseDuration(maxStr)
+		if err != nil {
+			return false, false, err
+		}
+
+		r := &rangeValues.IntegerRange[int64]{Min: int64(minValue), Max: int64(maxValue)}
+		return r.IsInRange(v.Int()), false, nil
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if r := rangeValues.ParseIntegerRange[int64](valueRange); r != nil {
+			return r.IsInRange(v.Int()), false, nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if r := rangeValues.ParseIntegerRange[uint64](valueRange); r != nil {
+			return r.IsInRange(v.Uint()), false, nil
+		}
+	case reflect.Float32, reflect.Float64:
+		if r := rangeValues.ParseRangeFloat64(valueRange); r != nil {
+			return r.IsInRange(v.Float()), false, nil
+		}
+	default:
+		if !hasLength(v) {
+			return false, false, fmt.Errorf("unsupported kind: %s", v.Kind())
+		}
+
+		if r := rangeValues.ParseIntegerRange[int](valueRange); r != nil {
+			return r.IsInRange(getLength(v)), true, nil
+		}
+	}
+
+	return false, false, errInvalid
+}
+
+// getCheckValues returns the string form of v, or of each of its elements if
+// it's a slice or an array.
+func getCheckValues(v reflect.Value) []string {
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		value, ok := formatFieldValue(v, "")
+		if !ok {
+			return nil
+		}
+
+		return []string{value}
+	}
+
+	values := make([]string, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		value, ok := formatFieldValue(v.Index(i), "")
+		if ok {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+func hasLength(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	}
+
+	return false
+}
+
+// getLength returns the length of v; strings are measured in runes.
+func getLength(v reflect.Value) int {
+	if v.Kind() == reflect.String {
+		return utf8.RuneCountInString(v.String())
+	}
+
+	return v.Len()
+}
+
+func lengthPrefix(isLength bool) string {
+	if isLength {
+		return "length "
+	}
+
+	return ""
+}
+
+func compareValues[T int | int64 | uint64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+
+	return 0
+}
+
+func containsString(values []string, value string) bool {
+	for _, current := range values {
+		if current == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isTrueTag(value string) bool {
+	result, err := extractBool("", value)
+	return err == nil && result
+}
+
+//---------------------------------------------------------
+
+func (e *ValidationError) Error() string {
+	var message string
+	switch {
+	case e.Section == "":
+		message = fmt.Sprintf("key '%s': %s", e.Key, e.Reason)
+	case e.Key == "":
+		message = fmt.Sprintf("section '%s': %s", e.Section, e.Reason)
+	default:
+		message = fmt.Sprintf("section '%s', key '%s': %s", e.Section, e.Key, e.Reason)
+	}
+
+	if e.Line != 0 {
+		message = fmt.Sprintf("line %d: %s", e.Line, message)
+	}
+
+	if e.Suggestion != "" {
+		message += fmt.Sprintf(" (did you mean '%s'?)", e.Suggestion)
+	}
+
+	return message
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+func (e ValidationErrors) Error() string {
+	if len(e) == 1 {
+		return "strongParser: validation failed: " + e[0].Error()
+	}
+
+	lines := make([]string, 0, len(e)+1)
+	lines = append(lines, fmt.Sprintf("strongParser: validation failed with %d errors:", len(e)))
+	for _, current := range e {
+		lines = append(lines, "\t"+current.Error())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, current := range e {
+		errs = append(errs, current)
+	}
+
+	return errs
+}
@@ -0,0 +1,167 @@
+package strongParser
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+// Current returns the current version of the config.
+func (w *ConfigWatcher[T]) Current() *T {
+	return w.current.Load()
+}
+
+// Reload parses the file again right away, as if it was changed. It returns
+// the error that is also reported to OnError, if any.
+func (w *ConfigWatcher[T]) Reload() error {
+	w.reloadMut.Lock()
+	defer w.reloadMut.Unlock()
+
+	newValue, files, err := w.parse()
+	if err == nil {
+		// the new content may include other files.
+		err = w.watchFiles(files)
+	}
+
+	if err != nil {
+		if w.options.OnError != nil {
+			w.options.OnError(err)
+		}
+
+		return err
+	}
+
+	oldValue := w.current.Swap(newValue)
+	diff := getChanges(oldValue, newValue)
+	if len(diff) != 0 && w.onChange != nil {
+		w.onChange(oldValue, newValue, diff)
+	}
+
+	return nil
+}
+
+// Close stops watching the file. Current keeps returning the last version.
+func (w *ConfigWatcher[T]) Close() error {
+	var err error
+	w.closeOnce.Do(func() {
+		close(w.done)
+
+		w.watchMut.Lock()
+		defer w.watchMut.Unlock()
+		if w.stopWatch != nil {
+			err = w.stopWatch()
+		}
+	})
+
+	return err
+}
+
+// parse parses and validates the file into a new value. Unlike ParseConfig,
+// a missing file is an error even if the environment may be read. It also
+// returns the files that have to be watched: the file itself, the files it
+// includes and its profile overlay.
+func (w *ConfigWatcher[T]) parse() (*T, []string, error) {
+	p, err := ParseWithOption(w.path, w.options.ParserOptions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	value := new(T)
+	err = parseFinalConfig(value, "", p)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	files := []string{w.path}
+	for _, current := range p.files {
+		if !slices.Contains(files, current) {
+			files = append(files, current)
+		}
+	}
+
+	return value, files, nil
+}
+
+// watchFiles makes w watch files, unless they are the ones it's already
+// watching.
+func (w *ConfigWatcher[T]) watchFiles(files []string) error {
+	w.watchMut.Lock()
+	defer w.watchMut.Unlock()
+
+	select {
+	case <-w.done:
+		// closed while reloading.
+		return nil
+	default:
+	}
+
+	if slices.Equal(files, w.files) {
+		return nil
+	}
+
+	stop, err := w.startWatching(files)
+	if err != nil {
+		return err
+	}
+
+	if w.stopWatch != nil {
+		_ = w.stopWatch()
+	}
+
+	w.files = files
+	w.stopWatch = stop
+	return nil
+}
+
+// startWatching starts sending to w.events when any of files might have
+// changed, and returns the function that stops it.
+func (w *ConfigWatcher[T]) startWatching(files []string) (func() error, error) {
+	if !w.options.Polling {
+		stop, err := startFileWatcher(files, w.events)
+		if err == nil {
+			return stop, nil
+		} else if !errors.Is(err, errWatchNotSupported) {
+			return nil, err
+		}
+	}
+
+	interval := w.options.Interval
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+
+	return startPolling(files, interval, w.events), nil
+}
+
+// run reloads the file after each change, once no more changes happen for
+// the debounce duration.
+func (w *ConfigWatcher[T]) run() {
+	debounce := w.options.Debounce
+	if debounce <= 0 {
+		debounce = defaultWatchDebounce
+	}
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-w.events:
+		}
+
+		timer := time.NewTimer(debounce)
+	waitLoop:
+		for {
+			select {
+			case <-w.done:
+				timer.Stop()
+				return
+			case <-w.events:
+				timer.Reset(debounce)
+			case <-timer.C:
+				break waitLoop
+			}
+		}
+
+		_ = w.Reload()
+	}
+}
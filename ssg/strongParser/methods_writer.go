@@ -0,0 +1,191 @@
+package strongParser
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// WriteTo writes the configuration to w in INI format, and returns the number
+// of bytes written.
+//
+// If the configuration was parsed from a source, the original layout is kept:
+// comments, blank lines and the order of the sections and their options are
+// preserved, and only the values changed through Set are rewritten. Options
+// removed with RemoveOption are dropped, new options are written after the
+// last option of their section, and new sections are appended at the end.
+func (p *ConfigParser) WriteTo(w io.Writer) (int64, error) {
+	counter := &countingWriter{w: w}
+	writer := bufio.NewWriter(counter)
+
+	p.writeLines(writer)
+	if err := writer.Flush(); err != nil {
+		return counter.n, err
+	}
+
+	return counter.n, nil
+}
+
+// String returns the configuration in INI format, as written by WriteTo.
+func (p *ConfigParser) String() string {
+	buf := &bytes.Buffer{}
+	_, _ = p.WriteTo(buf)
+	return buf.String()
+}
+
+// SaveFile writes the configuration to the named file (see WriteTo).
+// The file is replaced atomically: the content is written to a temporary file
+// in the same directory, which is then renamed over the original one. The mode
+// of an existing file is preserved.
+func (p *ConfigParser) SaveFile(filename string) error {
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(filename); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	tmpName := tmpFile.Name()
+	defer func() {
+		// no-op if the file was renamed successfully.
+		_ = os.Remove(tmpName)
+	}()
+
+	if _, err = p.WriteTo(tmpFile); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+
+	if err = tmpFile.Chmod(mode); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+
+	if err = tmpFile.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpName, filename)
+}
+
+// writeLines writes the original lines of the configuration (updated with the
+// current values), followed by the options and sections that were added.
+func (p *ConfigParser) writeLines(w *bufio.Writer) {
+	// originalKeys holds the options of each section that appear in the source;
+	// all of the other options of the section were added through the API.
+	originalKeys := make(map[*Section]map[string]bool)
+	// insertAfter holds the index of the line after which the added options of
+	// each section have to be written.
+	insertAfter := make(map[*Section]int)
+
+	for i, line := range p.lines {
+		switch line.kind {
+		case configLineSection:
+			if _, exists := insertAfter[line.section]; !exists {
+				insertAfter[line.section] = i
+			}
+		case configLineOption:
+			if originalKeys[line.section] == nil {
+				originalKeys[line.section] = make(map[string]bool)
+			}
+			originalKeys[line.section][line.section.safeKey(line.key)] = true
+			insertAfter[line.section] = i
+		}
+	}
+
+	insertions := make(map[int]*Section, len(insertAfter))
+	for section, index := range insertAfter {
+		insertions[index] = section
+	}
+
+	for i, line := range p.lines {
+		switch line.kind {
+		case configLineOption:
+			value, err := line.section.Get(line.key)
+			if err != nil {
+				// the option was removed.
+				break
+			}
+
+			if value == line.value {
+				writeLine(w, line.raw)
+			} else {
+				writeLine(w, line.prefix+formatOptionValue(value, p.options)+line.comment)
+			}
+		default:
+			writeLine(w, line.raw)
+		}
+
+		if section := insertions[i]; section != nil {
+			p.writeAddedOptions(w, section, originalKeys[section])
+		}
+	}
+
+	// sections that don't appear in the source at all.
+	newSections := make([]*Section, 0)
+	if _, exists := insertAfter[p.defaults]; !exists && p.hasOwnOptions(p.defaults) {
+		newSections = append(newSections, p.defaults)
+	}
+
+	for _, name := range p.sectionOrder {
+		section := p.config[name]
+		if _, exists := insertAfter[section]; exists || section == nil {
+			continue
+		}
+
+		if p.inherited[name] != nil && !p.hasOwnOptions(section) {
+			// the section only has options of included files.
+			continue
+		}
+
+		newSections = append(newSections, section)
+	}
+
+	for i, section := range newSections {
+		if i != 0 || len(p.lines) != 0 {
+			writeLine(w, "")
+		}
+
+		writeLine(w, "["+section.Name+"]")
+		p.writeAddedOptions(w, section, nil)
+	}
+}
+
+// writeAddedOptions writes the options of section that are not in originalKeys.
+func (p *ConfigParser) writeAddedOptions(w *bufio.Writer, section *Section, originalKeys map[string]bool) {
+	for _, key := range section.order {
+		if originalKeys[section.safeKey(key)] || p.isInherited(section, key) {
+			continue
+		}
+
+		writeLine(w, key+" = "+formatOptionValue(section.options[key], p.options))
+	}
+}
+
+// hasOwnOptions returns true if section has options that are not inherited
+// (see isInherited).
+func (p *ConfigParser) hasOwnOptions(section *Section) bool {
+	for _, key := range section.order {
+		if !p.isInherited(section, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func writeLine(w *bufio.Writer, line string) {
+	_, _ = w.WriteString(line)
+	_ = w.WriteByte('\n')
+}
+
+func (c *countingWriter) Write(b []byte) (int, error) {
+	n, err := c.w.Write(b)
+	c.n += int64(n)
+	return n, err
+}
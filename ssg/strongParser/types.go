@@ -1,9 +1,40 @@
 package strongParser
 
-import "reflect"
+import (
+	"io"
+	"io/fs"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
 
 type rValue = reflect.Value
 
+// ParseErrorKind is the kind of a problem found in a config source.
+type ParseErrorKind int
+
+// ParseError describes a line of a config source that couldn't be parsed.
+type ParseError struct {
+	// File is the name of the parsed file; it's empty for other sources.
+	File string
+
+	// Line and Column are the position of the problem, starting at 1.
+	Line   int
+	Column int
+
+	// Snippet is the text of the line, without leading and trailing spaces.
+	Snippet string
+	Kind    ParseErrorKind
+
+	// Err is the underlying error, if any, such as the error of the JSON
+	// decoder.
+	Err error
+}
+
+// ParseErrors holds all of the problems found in a config source.
+type ParseErrors []*ParseError
+
 // InvalidParseError describes an invalid argument passed to ParseConfig.
 // (The argument to ParseConfig must be a non-nil pointer.)
 type InvalidParseError struct {
@@ -14,6 +45,13 @@ type Section struct {
 	Name    string
 	options Dict
 	lookup  Dict
+
+	// order holds the option names in the order they were added.
+	order []string
+
+	// sources holds where the options of the source came from (by
+	// lower-cased option name); options added through the API have none.
+	sources map[string]*ValueSource
 }
 
 // Dict is a simple string->string map.
@@ -28,8 +66,159 @@ type ConfigParser struct {
 	config   Config
 	defaults *Section
 	options  *ConfigParserOptions
+
+	// lines holds every line of the parsed source, including comments and
+	// blank lines, so the configuration can be written back with its original
+	// layout.
+	lines []*configLine
+
+	// sectionOrder holds the section names in the order they were first seen
+	// or added.
+	sectionOrder []string
+
+	// violations holds the validation errors found while parsing a struct.
+	violations ValidationErrors
+
+	// includes holds the paths of the include directives of the source.
+	includes []string
+
+	// files holds the paths of the files the config was read from (including
+	// the files they include and profile overlays), in the order they were
+	// loaded; it's empty if the source is not a file.
+	files []string
+
+	// flatKeys is true if the source has no sections, such as a .env file:
+	// the options of its main section that are named like the environment
+	// variables of fields ("SECTION_KEY") fill these fields (see
+	// mapFlatKeys).
+	flatKeys bool
+
+	// inherited holds the options (by section name and lower-cased option
+	// name) that came from included files or lower layers, with their values.
+	// They are not written back unless they are changed.
+	inherited map[string]Dict
+
+	// sourceViolations holds the problems of the source found by the Strict
+	// option, such as duplicate keys; they are reported along with the
+	// violations of every parsed struct.
+	sourceViolations ValidationErrors
+
+	// dotEnv holds the variables of the DotEnvFiles option; nil if they are
+	// not loaded yet.
+	dotEnv map[string]string
+
+	// valueSources holds the sources of the values found while parsing a
+	// struct, by section name and lower-cased key; it's nil unless a report
+	// is requested (see ParseConfigWithReport).
+	valueSources map[string]map[string]*ValueSource
+
+	// knownKeys holds the lower-cased keys that were looked up while parsing
+	// a struct with the Strict option, by section name; a nil map means that
+	// every key of the section is known (such as the section of a map field).
+	knownKeys map[string]map[string]bool
+}
+
+// configLineKind describes what a line of a configuration source contains.
+type configLineKind int
+
+// configLine is a single line of a parsed configuration source.
+type configLine struct {
+	kind configLineKind
+	raw  string
+
+	// lineNo is the number of the line in the source (of its first line, if
+	// it spans several lines), starting at 1.
+	lineNo int
+
+	// section is the section this line belongs to (or declares).
+	section *Section
+
+	// key, prefix, value and comment are only set for option lines; prefix is
+	// the original text before the value (e.g. "key = "), and comment is the
+	// inline comment after it (including the spaces before it), if any.
+	key     string
+	prefix  string
+	value   string
+	comment string
+}
+
+// countingWriter is an io.Writer that counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+// lineParser reads a configuration source line by line into a ConfigParser.
+type lineParser struct {
+	p        *ConfigParser
+	options  *ConfigParserOptions
+	filename string
+	lineNo   int
+	curSect  *Section
+
+	// sectionLines holds the line number each section header of the source
+	// was first seen on.
+	sectionLines map[string]int
+
+	// errs holds the problems found in the source so far; they are all
+	// returned by finish.
+	errs ParseErrors
+
+	// optionLine and optionColumn are the position of the value of
+	// lastOption, for reporting an unterminated triple-quoted value.
+	optionLine   int
+	optionColumn int
+
+	// lastOption is the option line that the next lines may continue, and
+	// rawValue is its value as written in the source, with all of its lines.
+	lastOption *configLine
+	rawValue   string
+
+	// valueOpen is true if the value of lastOption must continue on the next
+	// line; valueQuoted is true if it's a quoted value.
+	valueOpen   bool
+	valueQuoted bool
 }
 
+// includeLoader loads config files and the files they include.
+type includeLoader struct {
+	options *ConfigParserOptions
+
+	// fsys is the file system the files are loaded from; nil for the file
+	// system of the OS.
+	fsys fs.FS
+
+	// dotEnv holds the variables of the DotEnvFiles option, which are passed
+	// on to the loaded files.
+	dotEnv map[string]string
+
+	// visiting holds the absolute paths of the files being loaded, in order;
+	// it's used to detect include cycles.
+	visiting []string
+
+	// files holds the paths of the files loaded so far, in order.
+	files []string
+}
+
+// Format reads config sources of a specific syntax into a ConfigParser, so
+// that they can be parsed into the same tagged structs; see INIFormat,
+// JSONFormat and DotEnvFormat.
+type Format interface {
+	// Parse reads the source from r. filename is the name of the source,
+	// used in the returned errors; it's empty if the source is not a file.
+	// Problems of the source are returned as ParseErrors.
+	Parse(r io.Reader, filename string, opt *ConfigParserOptions) (*ConfigParser, error)
+}
+
+// iniFormat is the Format of INI sources.
+type iniFormat struct{}
+
+// jsonFormat is the Format of JSON sources.
+type jsonFormat struct{}
+
+// dotEnvFormat is the Format of .env sources.
+type dotEnvFormat struct{}
+
 type MainAndArrayContainer[mT any, mA any] struct {
 	Main     *mT
 	Sections []*mA
@@ -42,6 +231,279 @@ type ChainMap struct {
 type ConfigParserOptions struct {
 	ReadEnv         bool
 	MainSectionName string
+
+	// Optional makes a missing config file (not the files it includes) be
+	// treated as an empty one, rather than an error.
+	Optional bool
+
+	// KeepNilPointers leaves nil pointer fields (such as *int and *string)
+	// nil when there is no value for them, so that a missing value can be
+	// told apart from one that is explicitly set to zero. By default, they
+	// are set to point to a zero value.
+	KeepNilPointers bool
+
+	// Format is the format of the parsed sources. If nil, the format of a
+	// file is picked by its extension: JSONFormat for ".json" files,
+	// DotEnvFormat for ".env" files and INIFormat for any other file;
+	// strings are always parsed with INIFormat.
+	Format Format
+
+	// Interpolate enables expanding references in values:
+	//   - ${section:key} is replaced by the value of key in section;
+	//   - %(key)s is replaced by the value of key in the same section (or in
+	//     the DEFAULT section);
+	//   - ${ENV_VAR} and ${ENV_VAR:-fallback} are replaced by the value of an
+	//     environment variable; fallback is used if it's unset or empty.
+	// Use $$ and %% to write a literal $ or %.
+	Interpolate bool
+
+	// MultilineValues allows values to span several lines: lines that are
+	// indented more than the option continue its value (joined with a
+	// newline), and so does the next line if a line ends with a backslash
+	// (joined without one).
+	MultilineValues bool
+
+	// QuotedValues allows single and double quoted values, which keep their
+	// leading and trailing spaces and support the \n, \t, \r, \\, \" and \'
+	// escape sequences, and triple-quoted (""" or ''') blocks that may span
+	// several lines.
+	QuotedValues bool
+
+	// InlineComments allows comments after values, starting with a ; or #
+	// that follows a space; lines starting with ; are comments too.
+	InlineComments bool
+
+	// Includes enables the include directives of config files: "include =
+	// path" lines before the first section, and the options of an [include]
+	// section, whose values are paths. Included files are loaded before the
+	// file that includes them, so its own values take precedence; relative
+	// paths are resolved from the directory of the including file.
+	// Sources that are not files (strings, bytes and readers) never include
+	// other files, and without this option [include] is a normal section.
+	Includes bool
+
+	// Profile is the name of the profile overlay to load on top of each
+	// parsed file, if it exists; for example "production" loads
+	// config.production.ini after config.ini. If empty, the environment
+	// variable named by ProfileEnv is used, if any.
+	Profile string
+
+	// ProfileEnv is the name of the environment variable holding the
+	// profile name (such as "CONFIG_PROFILE"). If empty, the profile is
+	// never read from the environment.
+	ProfileEnv string
+
+	// ResolveSecrets enables secret values: values starting with "file:" are
+	// replaced by the content of the named file (e.g. "file:/run/secrets/token"),
+	// and values starting with "enc:" are decrypted with the secret key (see
+	// EncryptValue). Also, when an environment variable is looked up and it's
+	// not set, the content of the file named by the same variable with a
+	// "_FILE" suffix is used, if it's set (e.g. TOKEN_FILE for TOKEN), as done
+	// with Docker secrets.
+	ResolveSecrets bool
+
+	// SecretKey is the AES key (16, 24 or 32 bytes long) of "enc:" values.
+	// If empty, the base64-encoded key is read from the environment variable
+	// named by SecretKeyEnv.
+	SecretKey []byte
+
+	// SecretKeyEnv is the name of the environment variable holding the
+	// secret key, DefaultSecretKeyEnv by default.
+	SecretKeyEnv string
+
+	// EnvPrefix is prepended to the name of every environment variable that
+	// is looked up, including the ones of `env` tags, interpolation references,
+	// ProfileEnv and SecretKeyEnv; for example with "MYBOT_", the token field
+	// of the main section is read from MYBOT_MAIN_TOKEN, MYBOT_token or
+	// MYBOT_TOKEN.
+	EnvPrefix string
+
+	// DotEnvFiles are .env files ("NAME=value" lines) whose variables are
+	// used as if they were set in the environment, without changing the
+	// environment of the process; variables that are actually set take
+	// precedence, and later files override earlier ones. Files that don't
+	// exist are ignored.
+	DotEnvFiles []string
+
+	// Strict makes ParseConfig (and the other functions that parse into a
+	// struct) report problems that are ignored otherwise, along with the
+	// validation errors: values that can't be converted to the type of their
+	// field, keys that don't match any field in the sections mapped to a
+	// struct, duplicate keys in a section and keys before the first section
+	// header. Each of them has the line number it was found on, if any, and
+	// unknown keys have a suggestion when they look like a typo.
+	Strict bool
+}
+
+// Validator can be implemented by config structs (and nested structs) to run
+// their own checks after they are parsed and their tags are validated.
+// The returned error is reported along with the other validation errors.
+type Validator interface {
+	Validate() error
+}
+
+// ValidationError describes a value that failed validation.
+type ValidationError struct {
+	Section string
+	// Key is empty for errors returned by a Validator.
+	Key    string
+	Reason string
+
+	// Err is the underlying error, if any (such as the error returned by a
+	// Validator, or ErrUnknownKey for the problems found by the Strict
+	// option).
+	Err error
+
+	// Line is the number of the line the problem was found on, or 0 if it's
+	// not about a specific line of the source.
+	Line int
+
+	// Suggestion is the known key that an unknown key was probably meant to
+	// be, if any.
+	Suggestion string
+}
+
+// InterpolationError describes a value whose references couldn't be
+// expanded.
+type InterpolationError struct {
+	Section string
+	Option  string
+	Err     error
+}
+
+// SecretError describes a secret value that couldn't be resolved, such as
+// a "file:" value whose file doesn't exist.
+type SecretError struct {
+	Section string
+	Option  string
+	Err     error
+}
+
+// ValidationErrors holds all of the validation errors of a parsed config.
+type ValidationErrors []*ValidationError
+
+// SourceKind is the kind of source a config value came from.
+type SourceKind int
+
+// ValueSource describes where the value of a config field came from; see
+// ParseConfigWithReport.
+type ValueSource struct {
+	Kind SourceKind
+
+	// File and Line are the location of the option, for SourceFile values.
+	// File is empty if the source is not a file, and Line is 0 if it's not
+	// known.
+	File string
+	Line int
+
+	// Env is the name of the environment variable, for SourceEnv values.
+	Env string
+
+	// Flag is the name of the flag, such as "--main.port", for SourceFlag
+	// values.
+	Flag string
+
+	// Value is the value as found in the source, before it's converted to
+	// the type of the field.
+	Value string
+
+	// secret is true if the value belongs to a secret field.
+	secret bool
+}
+
+// ConfigReport holds the source of the value of each field of a parsed
+// config that got one, by field path: "Database.Url" for nested structs,
+// "Bots[alpha].Token" for maps of structs, "Servers[0].Host" for slices of
+// structs and "Limits[ban]" for the entries of other maps.
+type ConfigReport map[string]*ValueSource
+
+// Change describes a field whose value differs between two versions of a
+// watched config.
+type Change struct {
+	// Field is the path of the field in the config struct, such as
+	// "Database.Url".
+	Field string
+	Old   any
+	New   any
+}
+
+// WatchOptions are the options of WatchConfig.
+type WatchOptions struct {
+	// ParserOptions are used to parse the config file.
+	ParserOptions *ConfigParserOptions
+
+	// Polling disables inotify on Linux; the file is checked for changes
+	// every Interval instead. Other platforms always use polling.
+	Polling bool
+
+	// Interval is the polling interval; 1 second by default.
+	Interval time.Duration
+
+	// Debounce is how long to wait for more changes after the file changes,
+	// before reloading it; 100 milliseconds by default.
+	Debounce time.Duration
+
+	// OnError is called when a reload fails, for example if the new content
+	// of the file is invalid; the last good config is kept.
+	OnError func(err error)
+}
+
+// ConfigWatcher holds the current version of a watched config file; see
+// WatchConfig.
+type ConfigWatcher[T any] struct {
+	path     string
+	options  *WatchOptions
+	onChange func(old, new *T, diff []Change)
+	current  atomic.Pointer[T]
+
+	// reloadMut makes sure reloads (and their callbacks) don't overlap.
+	reloadMut *sync.Mutex
+
+	// events receives a value when a watched file might have changed.
+	events    chan struct{}
+	done      chan struct{}
+	closeOnce *sync.Once
+
+	// watchMut guards files (the watched files) and stopWatch, which are
+	// replaced when a reload reads another set of files.
+	watchMut  *sync.Mutex
+	files     []string
+	stopWatch func() error
+}
+
+// fieldKind is the kind of a field found by walkFields.
+type fieldKind int
+
+// fieldInfo describes a field of a config struct that is filled from the
+// config file, as found by walkFields.
+type fieldInfo struct {
+	kind  fieldKind
+	field reflect.StructField
+
+	// path is the path of the field in the config struct, such as
+	// "Database.Url".
+	path string
+
+	// valueType is the type of the value of the field, without the pointer
+	// of pointer fields; for maps and slices of structs, it's the type of
+	// their elements.
+	valueType reflect.Type
+
+	// section and key are the location of the value. For maps and slices of
+	// structs, key is empty; for maps of structs, section is the prefix of
+	// their sections, and for slices of structs, it's their section pattern.
+	section string
+	key     string
+}
+
+// fieldFlag is the command-line flag of a field (see ParseConfigWithFlags).
+type fieldFlag struct {
+	info *fieldInfo
+
+	// value is the last value given to the flag; isSet is false if the flag
+	// was not given at all.
+	value string
+	isSet bool
 }
 
 type SectionValue interface {
@@ -49,4 +511,8 @@ type SectionValue interface {
 	GetSectionName() string
 }
 
-type fieldValueConverter[T comparable] func(fType, fValue string) (T, error)
+type fieldValueConverter[T any] func(fType, fValue string) (T, error)
+
+// valueDecoder decodes a value of a config file into a value of the type
+// it was created for.
+type valueDecoder func(value string) (reflect.Value, error)
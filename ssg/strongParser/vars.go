@@ -1,14 +1,53 @@
 package strongParser
 
 import (
+	"encoding"
 	"reflect"
 	"regexp"
+	"sync"
+	"time"
 )
 
 var (
-	sectionHeader      = regexp.MustCompile(`\[([^]]+)\]`)
+	sectionHeader      = regexp.MustCompile(`^\[([^]]+)\]`)
 	keyValue           = regexp.MustCompile(`([^:=\s][^:=]*)\s*(?P<vi>[:=])\s*(.*)$`)
+	dotEnvName         = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
 	DefaultMainSection = "main"
 )
 
+// The formats of config sources; see ConfigParserOptions.Format.
+var (
+	// INIFormat is the format of INI files, with the syntax enabled by the
+	// options (such as MultilineValues and Includes).
+	INIFormat Format = iniFormat{}
+
+	// JSONFormat is the format of JSON files. The members of the top-level
+	// object whose values are objects are sections, and nested objects are
+	// the sections named "section.key"; the other members of the top-level
+	// object belong to the main section. Arrays of scalar values are
+	// converted to lists, quoting the elements that need it; the objects of
+	// an array are the sections named "key.1", "key.2" and so on, which fill
+	// slices of structs. null values are ignored.
+	JSONFormat Format = jsonFormat{}
+
+	// DotEnvFormat is the format of .env files ("NAME=value" lines, see
+	// ConfigParserOptions.DotEnvFiles). A name in the form of "section.key"
+	// is the key of the named section (e.g. "database.url"), and other names
+	// belong to the main section; when parsing a struct, the ones named like
+	// the environment variable of a field (e.g. DATABASE_URL) fill that
+	// field instead.
+	DotEnvFormat Format = dotEnvFormat{}
+)
+
 var invalidReflectValue = reflect.ValueOf(nil)
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// decoders holds the decoders registered with RegisterDecoder.
+var (
+	decoders    = make(map[reflect.Type]valueDecoder)
+	decodersMut = &sync.RWMutex{}
+)
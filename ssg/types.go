@@ -20,6 +20,13 @@ type (
 	AdvancedMap[TKey comparable, TValue any] = mapUtils.AdvancedMap[TKey, TValue]
 	SafeEMap[TKey comparable, TValue any]    = mapUtils.SafeEMap[TKey, TValue]
 	SafeMap[TKey comparable, TValue any]     = mapUtils.SafeMap[TKey, TValue]
+
+	SafeValueMap[TKey comparable, TValue any]  = mapUtils.SafeValueMap[TKey, TValue]
+	SafeEValueMap[TKey comparable, TValue any] = mapUtils.SafeEValueMap[TKey, TValue]
+	CowMap[TKey comparable, TValue any]        = mapUtils.CowMap[TKey, TValue]
+
+	KeyedMutex[TKey comparable]   = mapUtils.KeyedMutex[TKey]
+	KeyedRWMutex[TKey comparable] = mapUtils.KeyedRWMutex[TKey]
 )
 
 type (
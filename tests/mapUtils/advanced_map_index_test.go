@@ -0,0 +1,158 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ALiwoto/ssg/ssg"
+	"github.com/ALiwoto/ssg/ssg/mapUtils"
+)
+
+type indexedUser struct {
+	Username string
+	ChatId   int64
+}
+
+func newIndexedUsersMap(t *testing.T) *ssg.AdvancedMap[int64, indexedUser] {
+	t.Helper()
+
+	m := ssg.NewAdvancedMap[int64, indexedUser]()
+	err := m.AddIndex("username", func(u *indexedUser) any {
+		if u.Username == "" {
+			return nil
+		}
+		return u.Username
+	}, true)
+	if err != nil {
+		t.Fatalf("AddIndex(username) returned %v", err)
+	}
+
+	err = m.AddIndex("chat", func(u *indexedUser) any {
+		return u.ChatId
+	}, false)
+	if err != nil {
+		t.Fatalf("AddIndex(chat) returned %v", err)
+	}
+
+	return m
+}
+
+func TestAdvancedMapIndexesFollowWrites(t *testing.T) {
+	m := newIndexedUsersMap(t)
+
+	m.Set(1, indexedUser{Username: "alice", ChatId: 10})
+	m.Set(2, indexedUser{Username: "bob", ChatId: 10})
+	m.Set(3, indexedUser{ChatId: 20})
+
+	if user := m.GetBy("username", "alice"); user == nil || user.ChatId != 10 {
+		t.Fatalf("GetBy(username, alice) returned %v", user)
+	}
+	if users := m.GetAllBy("chat", int64(10)); len(users) != 2 {
+		t.Fatalf("GetAllBy(chat, 10) returned %d users, want 2", len(users))
+	}
+
+	// re-setting a key must move it to its new index values.
+	m.Set(1, indexedUser{Username: "alice2", ChatId: 20})
+	if m.GetBy("username", "alice") != nil {
+		t.Fatal("old unique index value is still indexed after Set")
+	}
+	if user := m.GetBy("username", "alice2"); user == nil {
+		t.Fatal("new unique index value is not indexed after Set")
+	}
+	if users := m.GetAllBy("chat", int64(20)); len(users) != 2 {
+		t.Fatalf("GetAllBy(chat, 20) returned %d users, want 2", len(users))
+	}
+
+	m.Delete(2)
+	if m.GetBy("username", "bob") != nil || len(m.GetAllBy("chat", int64(10))) != 0 {
+		t.Fatal("deleted value is still indexed")
+	}
+
+	m.DeleteIf(3, func(u *indexedUser) bool { return true })
+	if keys := m.GetKeysBy("chat", int64(20)); len(keys) != 1 || keys[0] != 1 {
+		t.Fatalf("GetKeysBy(chat, 20) returned %v after DeleteIf, want [1]", keys)
+	}
+
+	m.ForEach(func(key int64, value *indexedUser) ssg.ForEachOperation {
+		return ssg.ForEachOperationRemove
+	})
+	if m.GetBy("username", "alice2") != nil {
+		t.Fatal("value removed by ForEach is still indexed")
+	}
+
+	m.Set(4, indexedUser{Username: "dave"})
+	m.Clear()
+	if m.GetBy("username", "dave") != nil {
+		t.Fatal("value removed by Clear is still indexed")
+	}
+}
+
+func TestAdvancedMapUniqueIndexRejectsConflicts(t *testing.T) {
+	m := newIndexedUsersMap(t)
+
+	if err := m.TrySet(1, indexedUser{Username: "alice", ChatId: 10}); err != nil {
+		t.Fatalf("TrySet returned %v", err)
+	}
+
+	err := m.TryAdd(2, &indexedUser{Username: "alice", ChatId: 30})
+	if !errors.Is(err, mapUtils.ErrUniqueIndexConflict) {
+		t.Fatalf("TryAdd returned %v, want ErrUniqueIndexConflict", err)
+	}
+	if m.Exists(2) || len(m.GetAllBy("chat", int64(30))) != 0 {
+		t.Fatal("a rejected value was partially stored")
+	}
+
+	m.Set(2, indexedUser{Username: "alice"})
+	if m.Exists(2) {
+		t.Fatal("Set stored a value rejected by a unique index")
+	}
+
+	// index values are compared with their dynamic type.
+	if m.GetBy("chat", 10) != nil || m.GetBy("chat", int64(10)) == nil {
+		t.Fatal("GetBy matched an index value of another type")
+	}
+
+	// setting the same key again with the same unique value is not a conflict.
+	if err := m.TrySet(1, indexedUser{Username: "alice", ChatId: 11}); err != nil {
+		t.Fatalf("re-setting the same key returned %v", err)
+	}
+
+	// users without a username are not indexed, so they never conflict.
+	m.Set(3, indexedUser{})
+	m.Set(4, indexedUser{})
+	if !m.Exists(3) || !m.Exists(4) {
+		t.Fatal("values with a nil index value were rejected")
+	}
+}
+
+func TestAdvancedMapAddIndexOnExistingValues(t *testing.T) {
+	m := ssg.NewAdvancedMap[int64, indexedUser]()
+	m.Set(1, indexedUser{Username: "alice", ChatId: 10})
+	m.Set(2, indexedUser{Username: "alice", ChatId: 10})
+
+	usernameFn := func(u *indexedUser) any { return u.Username }
+	err := m.AddIndex("username", usernameFn, true)
+	if !errors.Is(err, mapUtils.ErrUniqueIndexConflict) {
+		t.Fatalf("AddIndex returned %v, want ErrUniqueIndexConflict", err)
+	}
+	if m.HasIndex("username") {
+		t.Fatal("a unique index was added despite conflicting values")
+	}
+
+	if err := m.AddIndex("username", usernameFn, false); err != nil {
+		t.Fatalf("AddIndex returned %v", err)
+	}
+	if users := m.GetAllBy("username", "alice"); len(users) != 2 {
+		t.Fatalf("GetAllBy(username, alice) returned %d users, want 2", len(users))
+	}
+
+	err = m.AddIndex("username", usernameFn, false)
+	if !errors.Is(err, mapUtils.ErrIndexAlreadyExists) {
+		t.Fatalf("AddIndex with a duplicate name returned %v", err)
+	}
+
+	m.RemoveIndex("username")
+	if m.GetBy("username", "alice") != nil {
+		t.Fatal("GetBy returned a value from a removed index")
+	}
+}
@@ -0,0 +1,167 @@
+package tests
+
+import (
+	"reflect"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ALiwoto/ssg/ssg"
+	"github.com/ALiwoto/ssg/ssg/mapUtils"
+)
+
+func TestCowMapWritesPublishNewSnapshots(t *testing.T) {
+	m := ssg.NewCowMap[string, int]()
+	m.SetDefault(-1)
+
+	m.Set("a", 1)
+	before := m.Snapshot()
+	m.SetMany(map[string]int{"b": 2, "c": 3})
+
+	if len(before) != 1 || before["a"] != 1 {
+		t.Fatalf("old snapshot was modified by a write: %v", before)
+	}
+	if m.Length() != 3 || m.GetValue("c") != 3 {
+		t.Fatalf("SetMany was not applied: %v", m.ToNormalMap())
+	}
+	if value := m.GetValue("missing"); value != -1 {
+		t.Fatalf("GetValue(missing) returned %d, want the default value -1", value)
+	}
+
+	m.DeleteMany("a", "b", "missing")
+	if m.Exists("a") || m.Exists("b") || !m.Exists("c") {
+		t.Fatalf("DeleteMany left %v", m.ToNormalMap())
+	}
+
+	unchanged := m.Snapshot()
+	m.DeleteMany("missing", "other")
+	if reflect.ValueOf(m.Snapshot()).UnsafePointer() != reflect.ValueOf(unchanged).UnsafePointer() {
+		t.Fatal("DeleteMany swapped the snapshot without deleting anything")
+	}
+
+	m.DeleteIf("c", func(value int) bool { return value != 3 })
+	if !m.Exists("c") {
+		t.Fatal("DeleteIf removed a value for which condFn returned false")
+	}
+
+	m.Batch(func(values map[string]int) bool {
+		values["discarded"] = 1
+		return false
+	})
+	if m.Exists("discarded") {
+		t.Fatal("Batch published changes after fn returned false")
+	}
+
+	m.Batch(func(values map[string]int) bool {
+		delete(values, "c")
+		values["d"] = 4
+		return true
+	})
+	if m.Exists("c") || m.GetValue("d") != 4 {
+		t.Fatalf("Batch changes were not published: %v", m.ToNormalMap())
+	}
+
+	source := map[string]int{"x": 10}
+	m.Replace(source)
+	source["y"] = 20
+	if m.Length() != 1 || m.GetValue("x") != 10 {
+		t.Fatalf("Replace did not copy the source map: %v", m.ToNormalMap())
+	}
+
+	m.Clear()
+	if !m.IsEmpty() {
+		t.Fatal("Clear did not remove all entries")
+	}
+}
+
+func TestCowMapForEachSeesConsistentSnapshot(t *testing.T) {
+	m := mapUtils.NewCowMapFrom(map[int]int{1: 1, 2: 2, 3: 3})
+
+	visited := 0
+	m.ForEach(func(key, value int) ssg.ForEachOperation {
+		// writes made during the iteration must not be visible to it.
+		m.Set(key+100, value)
+		visited++
+		return ssg.ForEachOperationRemove
+	})
+
+	if visited != 3 {
+		t.Fatalf("ForEach visited %d entries, want 3", visited)
+	}
+	if m.Length() != 6 {
+		t.Fatalf("Length is %d, want 6", m.Length())
+	}
+}
+
+func TestCowMapConcurrentReadersAndWriters(t *testing.T) {
+	const (
+		readerCount = 8
+		writerCount = 4
+		opCount     = 500
+	)
+
+	m := ssg.NewCowMap[string, int]()
+	var wg sync.WaitGroup
+	var stop atomic.Bool
+	wg.Add(readerCount)
+	for range readerCount {
+		go func() {
+			defer wg.Done()
+			for !stop.Load() {
+				snapshot := m.Snapshot()
+				for key, value := range snapshot {
+					if strconv.Itoa(value) != key {
+						t.Errorf("inconsistent entry %q => %d", key, value)
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	var writers sync.WaitGroup
+	writers.Add(writerCount)
+	for worker := range writerCount {
+		go func() {
+			defer writers.Done()
+			for i := range opCount {
+				value := worker*opCount + i
+				m.Set(strconv.Itoa(value), value)
+			}
+		}()
+	}
+	writers.Wait()
+	stop.Store(true)
+	wg.Wait()
+
+	if m.Length() != writerCount*opCount {
+		t.Fatalf("Length is %d, want %d", m.Length(), writerCount*opCount)
+	}
+}
+
+func BenchmarkCowMapGetParallel(b *testing.B) {
+	m := ssg.NewCowMap[int, int]()
+	m.Replace(map[int]int{1: 1, 2: 2, 3: 3})
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			m.Get(2)
+		}
+	})
+}
+
+func BenchmarkSafeMapGetParallel(b *testing.B) {
+	m := ssg.NewSafeMap[int, int]()
+	m.Set(1, 1)
+	m.Set(2, 2)
+	m.Set(3, 3)
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			m.Get(2)
+		}
+	})
+}
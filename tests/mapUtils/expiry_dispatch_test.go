@@ -0,0 +1,228 @@
+package tests
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ALiwoto/ssg/ssg"
+	"github.com/ALiwoto/ssg/ssg/mapUtils"
+)
+
+// expireAll sets the values of keys in m, waits for them to expire, and runs
+// a check.
+func expireAll(m *ssg.SafeEMap[int, int], keys ...int) {
+	for _, key := range keys {
+		m.Set(key, key)
+	}
+	time.Sleep(5 * time.Millisecond)
+	m.DoCheck()
+}
+
+func TestSafeEMapSyncDispatchRunsAfterUnlock(t *testing.T) {
+	m := ssg.NewSafeEMap[int, int]()
+	m.SetExpiration(time.Millisecond)
+	m.SetExpiryDispatch(&mapUtils.ExpiryDispatchOptions{
+		Mode: mapUtils.ExpiryDispatchSync,
+	})
+
+	var called []int
+	m.SetOnExpired(func(key, value int) {
+		// the map must be usable from inside of a synchronous callback.
+		m.Exists(key)
+		called = append(called, key)
+	})
+
+	var batchSize int
+	m.SetOnExpiredBatch(func(entries []mapUtils.ExpiredEntry[int, int]) {
+		batchSize = len(entries)
+	})
+
+	expireAll(m, 1, 2, 3)
+
+	// synchronous callbacks are done when DoCheck returns.
+	if len(called) != 3 || batchSize != 3 {
+		t.Fatalf("callbacks were called for %v, batch size %d; want 3 and 3", called, batchSize)
+	}
+}
+
+func TestSafeEMapPoolDispatchIsBoundedAndOrdered(t *testing.T) {
+	const (
+		workers   = 2
+		keysCount = 50
+		rounds    = 5
+	)
+
+	m := ssg.NewSafeEMap[int, int]()
+	m.SetExpiration(time.Millisecond)
+	m.SetExpiryDispatch(&mapUtils.ExpiryDispatchOptions{
+		Mode:    mapUtils.ExpiryDispatchPool,
+		Workers: workers,
+	})
+
+	var (
+		running    atomic.Int32
+		maxRunning atomic.Int32
+		mut        sync.Mutex
+		seen       = make(map[int][]int)
+		wg         sync.WaitGroup
+	)
+	m.SetOnExpiredPtr(func(key int, value *int) {
+		defer wg.Done()
+
+		current := running.Add(1)
+		defer running.Add(-1)
+		for {
+			old := maxRunning.Load()
+			if current <= old || maxRunning.CompareAndSwap(old, current) {
+				break
+			}
+		}
+
+		time.Sleep(100 * time.Microsecond)
+		mut.Lock()
+		seen[key] = append(seen[key], *value)
+		mut.Unlock()
+	})
+
+	for round := range rounds {
+		wg.Add(keysCount)
+		for key := range keysCount {
+			m.Set(key, round)
+		}
+		time.Sleep(5 * time.Millisecond)
+		m.DoCheck()
+	}
+	wg.Wait()
+
+	if maxRunning.Load() > workers {
+		t.Fatalf("%d callbacks ran at the same time, want at most %d", maxRunning.Load(), workers)
+	}
+	for key, values := range seen {
+		for i, value := range values {
+			if value != i {
+				t.Fatalf("callbacks for key %d ran out of order: %v", key, values)
+			}
+		}
+	}
+}
+
+func TestSafeEMapExpiryCallbackPanicIsRecovered(t *testing.T) {
+	modes := []mapUtils.ExpiryDispatchMode{
+		mapUtils.ExpiryDispatchGoroutine,
+		mapUtils.ExpiryDispatchPool,
+		mapUtils.ExpiryDispatchSync,
+	}
+
+	for _, mode := range modes {
+		m := ssg.NewSafeEMap[int, int]()
+		m.SetExpiration(time.Millisecond)
+		m.SetExpiryDispatch(&mapUtils.ExpiryDispatchOptions{Mode: mode})
+
+		reported := make(chan int, 2)
+		m.SetOnCallbackPanic(func(key int, recovered any) {
+			if recovered != "boom" {
+				t.Errorf("recovered %v, want boom", recovered)
+			}
+			reported <- key
+		})
+
+		called := make(chan int, 2)
+		m.SetOnExpired(func(key, value int) {
+			panic("boom")
+		})
+		m.SetOnExpiredPtr(func(key int, value *int) {
+			called <- key
+		})
+
+		expireAll(m, 7)
+
+		for _, ch := range []chan int{reported, called} {
+			select {
+			case key := <-ch:
+				if key != 7 {
+					t.Fatalf("mode %d: got key %d, want 7", mode, key)
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("mode %d: callback was not called after a panic", mode)
+			}
+		}
+	}
+}
+
+func TestSafeEValueMapDispatchModes(t *testing.T) {
+	const workers = 2
+
+	// sync dispatch: callbacks are done when DoCheck returns, and the map
+	// is usable from inside of them.
+	m := ssg.NewSafeEValueMap[int, int]()
+	m.SetExpiration(time.Millisecond)
+	m.SetExpiryDispatch(&mapUtils.ExpiryDispatchOptions{Mode: mapUtils.ExpiryDispatchSync})
+
+	var called []int
+	var batch []mapUtils.ExpiredEntry[int, int]
+	m.SetOnExpired(func(key, value int) {
+		m.Exists(key)
+		called = append(called, value)
+	})
+	m.SetOnExpiredBatch(func(entries []mapUtils.ExpiredEntry[int, int]) {
+		if len(called) != len(entries) {
+			t.Errorf("the batch callback ran before the per-entry callbacks")
+		}
+		batch = entries
+	})
+
+	for key := range 3 {
+		m.Set(key, key)
+	}
+	time.Sleep(5 * time.Millisecond)
+	m.DoCheck()
+
+	if len(called) != 3 {
+		t.Fatalf("callbacks were called for %v, want 3 values", called)
+	}
+	if len(batch) != 3 || *batch[0].Value != batch[0].Key {
+		t.Fatalf("the batch callback got %v, want 3 entries", batch)
+	}
+
+	// pool dispatch: at most `workers` callbacks run at the same time.
+	m = ssg.NewSafeEValueMap[int, int]()
+	m.SetExpiration(time.Millisecond)
+	m.SetExpiryDispatch(&mapUtils.ExpiryDispatchOptions{
+		Mode:    mapUtils.ExpiryDispatchPool,
+		Workers: workers,
+	})
+
+	var (
+		running    atomic.Int32
+		maxRunning atomic.Int32
+		wg         sync.WaitGroup
+	)
+	m.SetOnExpired(func(key, value int) {
+		defer wg.Done()
+
+		current := running.Add(1)
+		defer running.Add(-1)
+		for {
+			old := maxRunning.Load()
+			if current <= old || maxRunning.CompareAndSwap(old, current) {
+				break
+			}
+		}
+
+		time.Sleep(100 * time.Microsecond)
+	})
+
+	wg.Add(50)
+	for key := range 50 {
+		m.Set(key, key)
+	}
+	time.Sleep(5 * time.Millisecond)
+	m.DoCheck()
+	wg.Wait()
+
+	if maxRunning.Load() > workers {
+		t.Fatalf("%d callbacks ran at the same time, want at most %d", maxRunning.Load(), workers)
+	}
+}
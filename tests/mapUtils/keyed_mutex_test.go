@@ -0,0 +1,161 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ALiwoto/ssg/ssg"
+)
+
+func TestKeyedMutexSerializesPerKey(t *testing.T) {
+	const (
+		workerCount = 16
+		opCount     = 200
+	)
+
+	m := ssg.NewKeyedMutex[int64]()
+	counters := make([]int, 4)
+
+	var wg sync.WaitGroup
+	wg.Add(workerCount)
+	for worker := range workerCount {
+		go func() {
+			defer wg.Done()
+			key := int64(worker % len(counters))
+			for range opCount {
+				m.Lock(key)
+				counters[key]++
+				m.Unlock(key)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for key, value := range counters {
+		if value != workerCount/len(counters)*opCount {
+			t.Fatalf("counter %d is %d, want %d", key, value, workerCount/len(counters)*opCount)
+		}
+	}
+	if m.Length() != 0 {
+		t.Fatalf("%d idle locks were not removed", m.Length())
+	}
+}
+
+func TestKeyedMutexDifferentKeysDoNotBlock(t *testing.T) {
+	m := ssg.NewKeyedMutex[string]()
+	m.Lock("a")
+
+	if !m.TryLock("b") {
+		t.Fatal("TryLock(b) failed while only a was locked")
+	}
+	if m.TryLock("a") {
+		t.Fatal("TryLock(a) succeeded while a was locked")
+	}
+	if !m.IsLocked("a") || m.Length() != 2 {
+		t.Fatalf("unexpected state: IsLocked(a)=%v, Length=%d", m.IsLocked("a"), m.Length())
+	}
+
+	m.Unlock("a")
+	m.Unlock("b")
+	if m.Length() != 0 {
+		t.Fatalf("%d idle locks were not removed", m.Length())
+	}
+}
+
+func TestKeyedMutexLockCtx(t *testing.T) {
+	m := ssg.NewKeyedMutex[string]()
+	m.Lock("key")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := m.LockCtx(ctx, "key"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("LockCtx returned %v, want context.DeadlineExceeded", err)
+	}
+
+	acquired := make(chan error)
+	go func() {
+		acquired <- m.LockCtx(context.Background(), "key")
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("LockCtx acquired a locked key")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	m.Unlock("key")
+	select {
+	case err := <-acquired:
+		if err != nil {
+			t.Fatalf("LockCtx returned %v after the key was unlocked", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("LockCtx did not acquire the key after it was unlocked")
+	}
+
+	m.Unlock("key")
+	if m.Length() != 0 {
+		t.Fatalf("%d idle locks were not removed", m.Length())
+	}
+}
+
+func TestKeyedMutexUnlockOfUnlockedKeyPanics(t *testing.T) {
+	m := ssg.NewKeyedMutex[string]()
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Unlock of an unlocked key did not panic")
+		}
+	}()
+	m.Unlock("key")
+}
+
+func TestKeyedRWMutexReadersAndWriters(t *testing.T) {
+	m := ssg.NewKeyedRWMutex[string]()
+
+	m.RLock("key")
+	if !m.TryRLock("key") {
+		t.Fatal("TryRLock failed while the key was only read-locked")
+	}
+	if m.TryLock("key") {
+		t.Fatal("TryLock succeeded while the key was read-locked")
+	}
+
+	writerDone := make(chan struct{})
+	go func() {
+		m.Lock("key")
+		close(writerDone)
+	}()
+
+	// a waiting writer must block new readers.
+	deadline := time.Now().Add(time.Second)
+	for m.TryRLock("key") {
+		m.RUnlock("key")
+		if time.Now().After(deadline) {
+			t.Fatal("the writer never started waiting")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := m.RLockCtx(ctx, "key"); err == nil {
+		t.Fatal("RLockCtx succeeded while a writer was waiting")
+	}
+
+	m.RUnlock("key")
+	m.RUnlock("key")
+	select {
+	case <-writerDone:
+	case <-time.After(time.Second):
+		t.Fatal("the writer did not acquire the key after the readers left")
+	}
+
+	m.Unlock("key")
+	if m.Length() != 0 {
+		t.Fatalf("%d idle locks were not removed", m.Length())
+	}
+}
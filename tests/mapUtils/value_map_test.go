@@ -0,0 +1,262 @@
+package tests
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ALiwoto/ssg/ssg"
+	"github.com/ALiwoto/ssg/ssg/mapUtils"
+)
+
+func TestSafeValueMapBasicOperations(t *testing.T) {
+	m := ssg.NewSafeValueMap[int64, int32]()
+	m.SetDefault(-1)
+
+	m.Set(1, 10)
+	m.Add(2, 20)
+	if value, ok := m.Get(1); !ok || value != 10 {
+		t.Fatalf("Get(1) returned (%d, %v), want (10, true)", value, ok)
+	}
+	if value, ok := m.Get(3); ok || value != 0 {
+		t.Fatalf("Get(3) returned (%d, %v), want (0, false)", value, ok)
+	}
+	if value := m.GetValue(3); value != -1 {
+		t.Fatalf("GetValue(3) returned %d, want the default value -1", value)
+	}
+
+	for range 5 {
+		m.Update(1, func(value int32, exists bool) int32 {
+			return value + 1
+		})
+	}
+	if value := m.GetValue(1); value != 15 {
+		t.Fatalf("GetValue(1) after Update returned %d, want 15", value)
+	}
+
+	if value := m.Update(4, func(value int32, exists bool) int32 {
+		if exists {
+			t.Fatal("Update reported a missing key as existing")
+		}
+		return value + 1
+	}); value != 0 {
+		t.Fatalf("Update on a missing key returned %d, want default+1 = 0", value)
+	}
+
+	m.DeleteIf(2, func(value int32) bool { return value != 20 })
+	if !m.Exists(2) {
+		t.Fatal("DeleteIf removed a value for which condFn returned false")
+	}
+	m.DeleteIf(2, func(value int32) bool { return value == 20 })
+	if m.Exists(2) {
+		t.Fatal("DeleteIf did not remove a value for which condFn returned true")
+	}
+
+	m.Disable()
+	m.Set(5, 50)
+	m.Delete(1)
+	m.Enable()
+	if m.Exists(5) || !m.Exists(1) {
+		t.Fatal("disabled map was modified")
+	}
+
+	m.ForEach(func(key int64, value int32) ssg.ForEachOperation {
+		return ssg.ForEachOperationRemove
+	})
+	if !m.IsEmpty() {
+		t.Fatalf("ForEach with remove operation left %d entries", m.Length())
+	}
+}
+
+func TestSafeValueMapConcurrentUpdate(t *testing.T) {
+	const (
+		workerCount = 16
+		updateCount = 1000
+	)
+
+	m := ssg.NewSafeValueMap[string, int]()
+	var wg sync.WaitGroup
+	wg.Add(workerCount)
+	for range workerCount {
+		go func() {
+			defer wg.Done()
+			for range updateCount {
+				m.Update("counter", func(value int, _ bool) int {
+					return value + 1
+				})
+			}
+		}()
+	}
+	wg.Wait()
+
+	if value := m.GetValue("counter"); value != workerCount*updateCount {
+		t.Fatalf("counter is %d, want %d", value, workerCount*updateCount)
+	}
+}
+
+func TestSafeEValueMapKeepsSlicesConsistent(t *testing.T) {
+	m := ssg.NewSafeEValueMap[int, int]()
+	m.SetExpiration(time.Hour)
+
+	for i := range 10 {
+		m.Set(i, i*10)
+	}
+	for i := 0; i < 10; i += 2 {
+		m.Delete(i)
+	}
+
+	if m.Length() != 5 {
+		t.Fatalf("Length is %d, want 5", m.Length())
+	}
+	for key, value := range m.ToNormalMap() {
+		if key%2 == 0 || value != key*10 {
+			t.Fatalf("unexpected entry %d => %d after deletes", key, value)
+		}
+	}
+	for range 20 {
+		key, ok := m.GetRandomKey()
+		if !ok || key%2 == 0 || !m.Exists(key) {
+			t.Fatalf("GetRandomKey returned (%d, %v)", key, ok)
+		}
+	}
+
+	m.ForEach(func(key, value int) ssg.ForEachOperation {
+		if key > 5 {
+			return ssg.ForEachOperationRemove
+		}
+		return ssg.ForEachOperationContinue
+	})
+	if m.Length() != 3 || m.Exists(7) || m.Exists(9) {
+		t.Fatalf("ForEach removal left %v", m.ToNormalMap())
+	}
+
+	m.Clear()
+	if !m.IsEmpty() {
+		t.Fatal("Clear did not remove all entries")
+	}
+	if _, ok := m.GetRandomKey(); ok {
+		t.Fatal("GetRandomKey returned a key after Clear")
+	}
+}
+
+func TestSafeEValueMapDoCheckExpiresValues(t *testing.T) {
+	m := ssg.NewSafeEValueMap[string, int]()
+	m.SetExpiration(20 * time.Millisecond)
+	m.SetPreExpiringConditionFn(func(key string, value int) bool {
+		return key != "kept"
+	})
+
+	expired := make(chan string, 2)
+	m.SetOnExpired(func(key string, value int) {
+		expired <- key
+	})
+
+	m.Set("expired", 1)
+	m.Set("kept", 2)
+	time.Sleep(30 * time.Millisecond)
+	m.Set("fresh", 3)
+	m.DoCheck()
+
+	select {
+	case key := <-expired:
+		if key != "expired" {
+			t.Fatalf("onExpired was called for %q", key)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("onExpired was not called")
+	}
+
+	if m.Exists("expired") || !m.Exists("kept") || !m.Exists("fresh") {
+		t.Fatalf("DoCheck left %v", m.ToNormalMap())
+	}
+
+	created := m.GetOrCreate("kept", func() (int, bool) {
+		return 20, true
+	})
+	if created != 2 {
+		t.Fatalf("GetOrCreate replaced a value kept by the pre-expiring condition: %d", created)
+	}
+}
+
+func TestSafeEValueMapConcurrentGetResetsTimestamp(t *testing.T) {
+	m := mapUtils.NewSafeEValueMap[int, int]()
+	m.SetExpiration(time.Hour)
+	for i := range 100 {
+		m.Set(i, i)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(8)
+	for worker := range 8 {
+		go func() {
+			defer wg.Done()
+			for i := range 1000 {
+				if worker%2 == 0 {
+					m.Get(i % 100)
+				} else {
+					m.Set(i%100, i)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if m.Length() != 100 {
+		t.Fatalf("Length is %d, want 100", m.Length())
+	}
+}
+
+const valueMapBenchmarkEntries = 100_000
+
+// measureHeapPerEntry fills a fresh map with valueMapBenchmarkEntries entries
+// b.N times, and reports the retained heap bytes per entry.
+func measureHeapPerEntry[T any](b *testing.B, newFn func() T, fillFn func(T, int64)) {
+	b.ReportAllocs()
+
+	var before, after runtime.MemStats
+	var totalBytes uint64
+	for range b.N {
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+
+		m := newFn()
+		for i := range int64(valueMapBenchmarkEntries) {
+			fillFn(m, i)
+		}
+
+		runtime.GC()
+		runtime.ReadMemStats(&after)
+		runtime.KeepAlive(m)
+
+		if after.HeapAlloc > before.HeapAlloc {
+			totalBytes += after.HeapAlloc - before.HeapAlloc
+		}
+	}
+
+	b.ReportMetric(float64(totalBytes)/float64(b.N)/valueMapBenchmarkEntries, "heap-B/entry")
+}
+
+func BenchmarkSafeMapInt64Int32Memory(b *testing.B) {
+	measureHeapPerEntry(b, ssg.NewSafeMap[int64, int32], func(m *ssg.SafeMap[int64, int32], i int64) {
+		m.Set(i, int32(i))
+	})
+}
+
+func BenchmarkSafeValueMapInt64Int32Memory(b *testing.B) {
+	measureHeapPerEntry(b, ssg.NewSafeValueMap[int64, int32], func(m *ssg.SafeValueMap[int64, int32], i int64) {
+		m.Set(i, int32(i))
+	})
+}
+
+func BenchmarkSafeEMapInt64Int32Memory(b *testing.B) {
+	measureHeapPerEntry(b, ssg.NewSafeEMap[int64, int32], func(m *ssg.SafeEMap[int64, int32], i int64) {
+		m.Set(i, int32(i))
+	})
+}
+
+func BenchmarkSafeEValueMapInt64Int32Memory(b *testing.B) {
+	measureHeapPerEntry(b, ssg.NewSafeEValueMap[int64, int32], func(m *ssg.SafeEValueMap[int64, int32], i int64) {
+		m.Set(i, int32(i))
+	})
+}
@@ -0,0 +1,151 @@
+package tests
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ALiwoto/ssg/ssg/rangeValues"
+	"github.com/ALiwoto/ssg/ssg/strongParser"
+)
+
+type logLevel int
+
+const (
+	logLevelInfo logLevel = iota
+	logLevelDebug
+)
+
+func (l *logLevel) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "info":
+		*l = logLevelInfo
+	case "debug":
+		*l = logLevelDebug
+	default:
+		return errors.New("unknown log level")
+	}
+
+	return nil
+}
+
+func (l logLevel) MarshalText() ([]byte, error) {
+	if l == logLevelDebug {
+		return []byte("debug"), nil
+	}
+
+	return []byte("info"), nil
+}
+
+// hexColor has no methods, it's decoded by a registered decoder.
+type hexColor uint32
+
+func init() {
+	strongParser.RegisterDecoder(func(value string) (hexColor, error) {
+		color, err := strconv.ParseUint(strings.TrimPrefix(value, "#"), 16, 32)
+		return hexColor(color), err
+	})
+}
+
+type decodedConfig struct {
+	Timeout   time.Duration
+	Backup    time.Duration
+	Intervals []time.Duration
+	Endpoint  url.URL
+	Webhook   *url.URL
+	Bind      net.IP
+	Peers     []net.IP
+	Filter    *regexp.Regexp
+	Ports     rangeValues.IntegerRange[int]
+	Level     logLevel
+	Color     hexColor
+	Missing   *url.URL
+}
+
+const decodedConfigValue = `
+[main]
+timeout = 1d12h
+backup = 2w
+intervals = 30s, 1h
+endpoint = https://api.example.com/v1
+webhook = https://hooks.example.com/bot
+bind = 127.0.0.1
+peers = 10.0.0.1, 10.0.0.2
+filter = ^/[a-z]+$
+ports = 1-65535
+level = debug
+color = #ff8800
+`
+
+func TestDecodedFields(t *testing.T) {
+	value := &decodedConfig{}
+	err := strongParser.ParseStringConfigWithOption(value, decodedConfigValue, noEnvOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value.Timeout != 36*time.Hour || value.Backup != 14*24*time.Hour {
+		t.Errorf("durations were not parsed: %v, %v", value.Timeout, value.Backup)
+	}
+	if len(value.Intervals) != 2 || value.Intervals[1] != time.Hour {
+		t.Errorf("Intervals is %v", value.Intervals)
+	}
+	if value.Endpoint.Host != "api.example.com" || value.Endpoint.Path != "/v1" {
+		t.Errorf("Endpoint is %v", value.Endpoint)
+	}
+	if value.Webhook == nil || value.Webhook.Host != "hooks.example.com" {
+		t.Errorf("Webhook is %v", value.Webhook)
+	}
+	if !value.Bind.Equal(net.IPv4(127, 0, 0, 1)) {
+		t.Errorf("Bind is %v", value.Bind)
+	}
+	if len(value.Peers) != 2 || !value.Peers[1].Equal(net.IPv4(10, 0, 0, 2)) {
+		t.Errorf("Peers is %v", value.Peers)
+	}
+	if value.Filter == nil || !value.Filter.MatchString("/start") {
+		t.Errorf("Filter is %v", value.Filter)
+	}
+	if value.Ports.Min != 1 || value.Ports.Max != 65535 {
+		t.Errorf("Ports is %+v", value.Ports)
+	}
+	if value.Level != logLevelDebug {
+		t.Errorf("Level is %v", value.Level)
+	}
+	if value.Color != 0xff8800 {
+		t.Errorf("Color is %x", value.Color)
+	}
+	if value.Missing != nil {
+		t.Errorf("Missing was allocated without a value: %v", value.Missing)
+	}
+}
+
+func TestMarshalDecodedFields(t *testing.T) {
+	value := &decodedConfig{}
+	err := strongParser.ParseStringConfigWithOption(value, decodedConfigValue, noEnvOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := strongParser.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{
+		"timeout = 36h0m0s",
+		"endpoint = https://api.example.com/v1",
+		"bind = 127.0.0.1",
+		"filter = ^/[a-z]+$",
+		"ports = 1-65535",
+		"level = debug",
+	} {
+		if !strings.Contains(string(data), line+"\n") {
+			t.Errorf("Marshal output doesn't contain %q:\n%s", line, data)
+		}
+	}
+}
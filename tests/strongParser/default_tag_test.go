@@ -0,0 +1,109 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ALiwoto/ssg/ssg/strongParser"
+)
+
+type defaultsConfig struct {
+	Port      int             `default:"8080"`
+	Host      string          `default:"localhost"`
+	Debug     bool            `default:"true"`
+	Timeout   time.Duration   `default:"1d12h"`
+	Retries   *int            `default:"3"`
+	OwnerIds  []int64         `default:"1, 2, 3"`
+	Intervals []time.Duration `default:"1s, 2m"`
+	Ratio     float64
+	Limit     *int
+}
+
+// noEnvOptions returns options that don't read values from the environment.
+func noEnvOptions() *strongParser.ConfigParserOptions {
+	return &strongParser.ConfigParserOptions{
+		MainSectionName: strongParser.DefaultMainSection,
+	}
+}
+
+func TestDefaultTags(t *testing.T) {
+	value := &defaultsConfig{}
+	err := strongParser.ParseStringConfigWithOption(value, "[main]\n", noEnvOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value.Port != 8080 || value.Host != "localhost" || !value.Debug {
+		t.Errorf("scalar defaults were not applied: %+v", value)
+	}
+	if value.Timeout != 36*time.Hour {
+		t.Errorf("Timeout is %v, want 36h", value.Timeout)
+	}
+	if value.Retries == nil || *value.Retries != 3 {
+		t.Errorf("Retries is %v, want a pointer to 3", value.Retries)
+	}
+	if len(value.OwnerIds) != 3 || value.OwnerIds[2] != 3 {
+		t.Errorf("OwnerIds is %v, want [1 2 3]", value.OwnerIds)
+	}
+	if len(value.Intervals) != 2 || value.Intervals[1] != 2*time.Minute {
+		t.Errorf("Intervals is %v, want [1s 2m0s]", value.Intervals)
+	}
+	if value.Limit == nil || *value.Limit != 0 {
+		t.Errorf("Limit is %v, want a pointer to 0", value.Limit)
+	}
+
+	// with KeepNilPointers, a nil pointer means a missing value.
+	opt := noEnvOptions()
+	opt.KeepNilPointers = true
+	value = &defaultsConfig{}
+	err = strongParser.ParseStringConfigWithOption(value, "[main]\n", opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value.Limit != nil {
+		t.Errorf("Limit was allocated without a value: %v", *value.Limit)
+	}
+	if value.Retries == nil || *value.Retries != 3 {
+		t.Errorf("Retries is %v, want a pointer to 3", value.Retries)
+	}
+}
+
+func TestExplicitZeroOverridesDefault(t *testing.T) {
+	const config = `
+[main]
+port = 0
+debug = false
+timeout = 0s
+retries = 0
+ratio = 0
+limit = 0
+`
+	value := &defaultsConfig{Ratio: 0.5}
+	err := strongParser.ParseStringConfigWithOption(value, config, noEnvOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value.Port != 0 || value.Debug || value.Timeout != 0 || value.Ratio != 0 {
+		t.Errorf("explicit zero values were not applied: %+v", value)
+	}
+	if value.Retries == nil || *value.Retries != 0 {
+		t.Errorf("Retries is %v, want a pointer to 0", value.Retries)
+	}
+	if value.Limit == nil || *value.Limit != 0 {
+		t.Errorf("Limit is %v, want a pointer to 0", value.Limit)
+	}
+}
+
+func TestMissingValueKeepsField(t *testing.T) {
+	value := &defaultsConfig{Ratio: 0.5}
+	err := strongParser.ParseStringConfigWithOption(value, "[main]\n", noEnvOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value.Ratio != 0.5 {
+		t.Errorf("Ratio is %v, a missing value must not change it", value.Ratio)
+	}
+}
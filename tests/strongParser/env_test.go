@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ALiwoto/ssg/ssg/strongParser"
+)
+
+type envPrefixConfig struct {
+	Token  string
+	Port   int
+	Admins []int64
+	Owner  string `env:"OWNER"`
+	Greet  string
+}
+
+func TestEnvPrefix(t *testing.T) {
+	t.Setenv("TOKEN", "unprefixed")
+	t.Setenv("ENVTEST_MAIN_TOKEN", "prefixed")
+	t.Setenv("ENVTEST_ADMINS", "1, 2")
+	t.Setenv("ENVTEST_OWNER", "me")
+	t.Setenv("ENVTEST_NAME", "world")
+
+	opt := &strongParser.ConfigParserOptions{
+		ReadEnv:         true,
+		MainSectionName: strongParser.DefaultMainSection,
+		Interpolate:     true,
+		EnvPrefix:       "ENVTEST_",
+	}
+
+	config := &envPrefixConfig{}
+	err := strongParser.ParseStringConfigWithOption(config, "[main]\ngreet = hello ${NAME}\n", opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.Token != "prefixed" || len(config.Admins) != 2 || config.Owner != "me" ||
+		config.Greet != "hello world" {
+		t.Errorf("got %+v", config)
+	}
+}
+
+func TestDotEnvFiles(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"base.env": "# shared values\nexport TOKEN=base-token\nPORT=80\n" +
+			"GREETING=\"hello\\nworld\"\nRAW='a # b'\n",
+		"local.env": "PORT=8080 # overrides base.env\n",
+	})
+	t.Setenv("DOTENV_TEST_OWNER", "from-process")
+
+	type dotEnvConfig struct {
+		Token    string
+		Port     int
+		Greeting string
+		Raw      string
+		Owner    string `env:"DOTENV_TEST_OWNER"`
+	}
+
+	opt := &strongParser.ConfigParserOptions{
+		ReadEnv:         true,
+		MainSectionName: strongParser.DefaultMainSection,
+		DotEnvFiles: []string{
+			filepath.Join(dir, "base.env"),
+			filepath.Join(dir, "missing.env"),
+			filepath.Join(dir, "local.env"),
+		},
+	}
+	writeFile(t, filepath.Join(dir, "config.ini"), "[main]\n")
+
+	config := &dotEnvConfig{}
+	err := strongParser.ParseConfigWithOption(config, filepath.Join(dir, "config.ini"), opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := dotEnvConfig{
+		Token:    "base-token",
+		Port:     8080,
+		Greeting: "hello\nworld",
+		Raw:      "a # b",
+		Owner:    "from-process",
+	}
+	if *config != expected {
+		t.Errorf("got %+v, want %+v", *config, expected)
+	}
+
+	if _, present := os.LookupEnv("GREETING"); present {
+		t.Error("the environment of the process was changed")
+	}
+
+	// the variables of .env files are prefixed too.
+	writeFile(t, filepath.Join(dir, "prefixed.env"), "BOT_TOKEN=prefixed\n")
+	opt.DotEnvFiles = []string{filepath.Join(dir, "prefixed.env")}
+	opt.EnvPrefix = "BOT_"
+	config = &dotEnvConfig{}
+	if err = strongParser.ParseStringConfigWithOption(config, "[main]\n", opt); err != nil {
+		t.Fatal(err)
+	}
+	if config.Token != "prefixed" {
+		t.Errorf("got token %q, want prefixed", config.Token)
+	}
+}
+
+func TestDotEnvFileErrors(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"bad.env": "GOOD=1\nnot a variable\nQUOTED=\"unterminated\n",
+	})
+
+	opt := noEnvOptions()
+	opt.DotEnvFiles = []string{filepath.Join(dir, "bad.env")}
+	err := strongParser.ParseStringConfigWithOption(&envPrefixConfig{}, "[main]\n", opt)
+
+	var parseErrs strongParser.ParseErrors
+	if !errors.As(err, &parseErrs) || len(parseErrs) != 2 ||
+		parseErrs[0].Line != 2 || parseErrs[1].Line != 3 {
+		t.Errorf("got %v, want errors on lines 2 and 3", err)
+	}
+}
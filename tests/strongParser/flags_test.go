@@ -0,0 +1,127 @@
+package tests
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ALiwoto/ssg/ssg/strongParser"
+)
+
+type flagsConfig struct {
+	Port    int `desc:"Port to listen on." default:"80"`
+	Debug   bool
+	Timeout time.Duration `default:"5s"`
+	Admins  []int64
+	Token   string `env:"FLAGS_TEST_TOKEN"`
+
+	Database struct {
+		Url string `required:"true"`
+	}
+}
+
+func TestParseConfigWithFlags(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"config.ini": "[main]\nport = 8080\nadmins = 1, 2\ntoken = from-file\n\n" +
+			"[database]\nurl = file-db\n",
+	})
+	t.Setenv("FLAGS_TEST_TOKEN", "from-env")
+
+	config := &flagsConfig{}
+	err := strongParser.ParseConfigWithFlags(config, filepath.Join(dir, "config.ini"), []string{
+		"--main.port=9090", "--main.debug", "-main.token", "from-flag",
+		"--database.url", "flag-db",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.Port != 9090 || !config.Debug || config.Timeout != 5*time.Second ||
+		len(config.Admins) != 2 || config.Token != "from-flag" || config.Database.Url != "flag-db" {
+		t.Errorf("got %+v", config)
+	}
+
+	// flags that are not given leave the file and env values alone.
+	config = &flagsConfig{}
+	err = strongParser.ParseConfigWithFlags(config, filepath.Join(dir, "config.ini"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.Port != 8080 || config.Debug || config.Token != "from-file" {
+		t.Errorf("got %+v", config)
+	}
+}
+
+func TestParseConfigWithFlagsErrors(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"config.ini": "[database]\nurl = db\n",
+	})
+	filename := filepath.Join(dir, "config.ini")
+
+	usage := captureStderr(t, func() {
+		err := strongParser.ParseConfigWithFlags(&flagsConfig{}, filename, []string{"--main.port", "abc"})
+		if err == nil || !strings.Contains(err.Error(), "main.port") {
+			t.Errorf("got error %v for an invalid int flag", err)
+		}
+	})
+
+	if !strings.Contains(usage, "--main.port int\n") ||
+		!strings.Contains(usage, "Port to listen on. (default: 80; env: ") {
+		t.Errorf("unexpected usage:\n%s", usage)
+	}
+
+	usage = captureStderr(t, func() {
+		err := strongParser.ParseConfigWithFlags(&flagsConfig{}, filename, []string{"--help"})
+		if !errors.Is(err, flag.ErrHelp) {
+			t.Errorf("got error %v, want flag.ErrHelp", err)
+		}
+	})
+
+	for _, expected := range []string{
+		"  --main.debug\n", "--main.timeout time.Duration", "--main.admins []int64",
+		"env: FLAGS_TEST_TOKEN", "--database.url string\n    \t(required; env: ",
+	} {
+		if !strings.Contains(usage, expected) {
+			t.Errorf("usage doesn't contain %q:\n%s", expected, usage)
+		}
+	}
+
+	captureStderr(t, func() {
+		err := strongParser.ParseConfigWithFlags(&flagsConfig{}, filename, []string{"--main.unknown=1"})
+		if err == nil {
+			t.Error("an unknown flag was accepted")
+		}
+	})
+}
+
+// captureStderr returns what fn writes to os.Stderr.
+func captureStderr(t *testing.T, fn func()) string {
+	t.Helper()
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stderr := os.Stderr
+	os.Stderr = writer
+	defer func() {
+		os.Stderr = stderr
+	}()
+
+	output := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(reader)
+		output <- string(b)
+	}()
+
+	fn()
+	_ = writer.Close()
+	return <-output
+}
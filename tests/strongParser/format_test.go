@@ -0,0 +1,199 @@
+package tests
+
+import (
+	"errors"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/ALiwoto/ssg/ssg/strongParser"
+)
+
+type formatConfig struct {
+	Token    string
+	Admins   []int64
+	Debug    bool
+	Database dbConfig
+}
+
+type formatBot struct {
+	Token string
+	Owner int64
+	name  string
+}
+
+func (b *formatBot) SetSectionName(name string) {
+	b.name = name
+}
+
+func (b *formatBot) GetSectionName() string {
+	return b.name
+}
+
+func TestJSONFormat(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"config.json": `{
+	"token": "12345:abcd",
+	"admins": [1, 2, 3],
+	"debug": true,
+	"database": {
+		"url": "postgres://localhost/db",
+		"max_connections": 10,
+		"replica": {"host": "replica.local", "port": 5433},
+		"unused": null
+	}
+}`,
+	})
+
+	config := &formatConfig{}
+	err := strongParser.ParseConfigWithOption(config, filepath.Join(dir, "config.json"), noEnvOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.Token != "12345:abcd" || len(config.Admins) != 3 || config.Admins[2] != 3 || !config.Debug {
+		t.Errorf("got %+v", config)
+	}
+
+	db := config.Database
+	if db.Url != "postgres://localhost/db" || db.MaxConn != 10 ||
+		db.Replica.Host != "replica.local" || db.Replica.Port != 5433 {
+		t.Errorf("got %+v", db)
+	}
+}
+
+type jsonSliceConfig struct {
+	Names   []string
+	Servers []serverConfig `section:"servers"`
+}
+
+func TestJSONFormatArrays(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"config.json": `{
+	"names": ["a b", "c, d", "e"],
+	"servers": [
+		{"host": "first.local", "tls": {"cert": "first.pem"}},
+		{"host": "second.local", "port": 8080}
+	]
+}`,
+	})
+
+	config := &jsonSliceConfig{}
+	err := strongParser.ParseConfigWithOption(config, filepath.Join(dir, "config.json"), noEnvOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(config.Names) != 3 || config.Names[0] != "a b" || config.Names[1] != "c, d" {
+		t.Errorf("Names is %q", config.Names)
+	}
+
+	servers := config.Servers
+	if len(servers) != 2 || servers[0].Host != "first.local" || servers[0].Port != 80 ||
+		servers[0].Tls == nil || servers[0].Tls.Cert != "first.pem" ||
+		servers[1].Host != "second.local" || servers[1].Port != 8080 {
+		t.Fatalf("Servers is %+v", servers)
+	}
+	if servers[0].GetSectionName() != "servers.1" || servers[1].GetSectionName() != "servers.2" {
+		t.Errorf("got sections %q and %q", servers[0].GetSectionName(), servers[1].GetSectionName())
+	}
+}
+
+func TestDotEnvFormat(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"config.env": "TOKEN=12345:abcd\nadmins=\"1, 2\"\n" +
+			"database.url=postgres://localhost/db\ndatabase.replica.host=replica.local\n",
+		"plain.env": "TOKEN=12345:abcd\nDATABASE_URL=postgres://localhost/db\n" +
+			"DATABASE_MAX_CONNECTIONS=10\nDATABASE_REPLICA_HOST=replica.local\n",
+	})
+
+	config := &formatConfig{}
+	err := strongParser.ParseConfigWithOption(config, filepath.Join(dir, "config.env"), noEnvOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.Token != "12345:abcd" || len(config.Admins) != 2 ||
+		config.Database.Url != "postgres://localhost/db" || config.Database.Replica.Host != "replica.local" {
+		t.Errorf("got %+v", config)
+	}
+
+	// names like the environment variables of the fields fill them too.
+	opt := noEnvOptions()
+	opt.Strict = true
+	config = &formatConfig{}
+	err = strongParser.ParseConfigWithOption(config, filepath.Join(dir, "plain.env"), opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.Token != "12345:abcd" || config.Database.Url != "postgres://localhost/db" ||
+		config.Database.MaxConn != 10 || config.Database.Replica.Host != "replica.local" {
+		t.Errorf("got %+v", config)
+	}
+}
+
+func TestFormatOption(t *testing.T) {
+	opt := noEnvOptions()
+	opt.Format = strongParser.JSONFormat
+
+	container, err := strongParser.ParseMainAndArraysStr[formatConfig, formatBot](`{
+	"token": "main-token",
+	"first_bot": {"token": "first", "owner": 1},
+	"second_bot": {"token": "second", "owner": 2}
+}`, opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if container.Main.Token != "main-token" || len(container.Sections) != 2 {
+		t.Fatalf("got %+v", container)
+	}
+
+	sort.Slice(container.Sections, func(i, j int) bool {
+		return container.Sections[i].Owner < container.Sections[j].Owner
+	})
+
+	first, second := container.Sections[0], container.Sections[1]
+	if first.GetSectionName() != "first_bot" || first.Token != "first" ||
+		second.GetSectionName() != "second_bot" || second.Token != "second" {
+		t.Errorf("got %+v and %+v", first, second)
+	}
+}
+
+func TestJSONFormatErrors(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"syntax.json": "{\n\t\"token\": \"abc\",\n\t\"admins\": [1, 2,]\n}\n",
+		"array.json":  "[1, 2]",
+		"nested.json": "{\"admins\": [[1]]}",
+		"mixed.json":  "{\"admins\": [{}, 1]}",
+	})
+
+	tests := []struct {
+		name   string
+		line   int
+		column int
+	}{
+		{"syntax.json", 3, 17},
+		{"array.json", 1, 1},
+		{"nested.json", 1, 13},
+		{"mixed.json", 1, 17},
+	}
+
+	for _, test := range tests {
+		path := filepath.Join(dir, test.name)
+		err := strongParser.ParseConfigWithOption(&formatConfig{}, path, noEnvOptions())
+
+		var parseErrs strongParser.ParseErrors
+		if !errors.As(err, &parseErrs) || len(parseErrs) != 1 {
+			t.Errorf("%s: got error %v, want ParseErrors", test.name, err)
+			continue
+		}
+
+		parseErr := parseErrs[0]
+		if parseErr.File != path || parseErr.Line != test.line || parseErr.Column != test.column ||
+			parseErr.Kind != strongParser.ParseErrorInvalidDocument || parseErr.Err == nil {
+			t.Errorf("%s: got %+v", test.name, parseErr)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"testing/iotest"
+
+	"github.com/ALiwoto/ssg/ssg/strongParser"
+)
+
+func TestParseReader(t *testing.T) {
+	// a reader that returns a single byte at a time.
+	reader := iotest.OneByteReader(strings.NewReader(nestedConfigValue))
+
+	config := &nestedConfig{}
+	err := strongParser.ParseReaderConfigWithOption(config, reader, noEnvOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.Token != "12345:abcd" || config.Database.Url != "postgres://localhost/db" {
+		t.Errorf("got %+v", config)
+	}
+
+	p, err := strongParser.ParseReader(strings.NewReader("[main]\ntoken = abc\n[main]\n"))
+	var parseErrs strongParser.ParseErrors
+	if p != nil || !errors.As(err, &parseErrs) || parseErrs[0].Line != 3 {
+		t.Errorf("got %v, %v for a duplicate section", p, err)
+	}
+}
+
+func TestParseConfigFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"configs/config.ini":            {Data: []byte("include = base.ini\n\n[main]\ntoken = 12345:abcd\n")},
+		"configs/base.ini":              {Data: []byte("[database]\nurl = postgres://localhost/db\n")},
+		"configs/config.production.ini": {Data: []byte("[database]\nmax_connections = 20\n")},
+		"configs/settings.json":         {Data: []byte(`{"token": "json-token"}`)},
+	}
+
+	opt := includeOptions()
+	opt.Profile = "production"
+
+	config := &nestedConfig{}
+	err := strongParser.ParseConfigFSWithOption(config, fsys, "configs/config.ini", opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.Token != "12345:abcd" || config.Database.Url != "postgres://localhost/db" ||
+		config.Database.MaxConn != 20 {
+		t.Errorf("got %+v", config)
+	}
+
+	config = &nestedConfig{}
+	err = strongParser.ParseConfigFSWithOption(config, fsys, "configs/settings.json", noEnvOptions())
+	if err != nil || config.Token != "json-token" {
+		t.Errorf("got %+v, %v", config, err)
+	}
+
+	_, err = strongParser.ParseFS(fsys, "configs/missing.ini")
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("got error %v for a missing file", err)
+	}
+}
+
+func TestOptionalOption(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"broken.ini": "include = missing.ini\n",
+	})
+
+	opt := includeOptions()
+	opt.Optional = true
+
+	config := &defaultsConfig{}
+	err := strongParser.ParseConfigWithOption(config, filepath.Join(dir, "missing.ini"), opt)
+	if err != nil {
+		t.Fatalf("got error %v for a missing optional file", err)
+	}
+
+	// the default tags are used, as for an empty file.
+	if config.Port != 8080 || config.Host != "localhost" || len(config.OwnerIds) != 3 {
+		t.Errorf("got %+v", config)
+	}
+
+	// the files included by an optional file are not optional.
+	_, err = strongParser.ParseWithOption(filepath.Join(dir, "broken.ini"), opt)
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("got error %v for a missing include", err)
+	}
+
+	_, err = strongParser.ParseWithOption(filepath.Join(dir, "missing.ini"), noEnvOptions())
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("got error %v for a missing file that is not optional", err)
+	}
+}
@@ -0,0 +1,210 @@
+package tests
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ALiwoto/ssg/ssg/strongParser"
+)
+
+// writeConfigFiles writes files (name -> content) into a new temporary
+// directory, and returns its path.
+func writeConfigFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+// includeOptions returns options that enable the include directives.
+func includeOptions() *strongParser.ConfigParserOptions {
+	opt := noEnvOptions()
+	opt.Includes = true
+	return opt
+}
+
+func TestIncludeDirectives(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"shared/base.ini":  "[main]\ntoken = base\nowner_id = 1\n\n[database]\nurl = postgres://base\n",
+		"shared/extra.ini": "[main]\nowner_id = 2\n",
+		"config.ini": "include = shared/base.ini\n\n[main]\ntoken = own\n\n" +
+			"[include]\nextra = shared/extra.ini\n",
+	})
+
+	p, err := strongParser.ParseWithOption(filepath.Join(dir, "config.ini"), includeOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[[2]string]string{
+		{"main", "token"}:    "own",
+		{"main", "owner_id"}: "2",
+		{"database", "url"}:  "postgres://base",
+	}
+	for key, want := range expected {
+		if got, _ := p.Get(key[0], key[1]); got != want {
+			t.Errorf("Get(%s, %s) = %q, want %q", key[0], key[1], got, want)
+		}
+	}
+	if p.HasSection("include") {
+		t.Error("the [include] section is listed as a config section")
+	}
+
+	// included options are not written back into the including file.
+	output := p.String()
+	if strings.Contains(output, "postgres://base") || strings.Contains(output, "owner_id") {
+		t.Errorf("included options were written back:\n%s", output)
+	}
+
+	_ = p.Set("database", "url", "postgres://changed")
+	if !strings.Contains(p.String(), "[database]\nurl = postgres://changed\n") {
+		t.Errorf("a changed included option was not written back:\n%s", p.String())
+	}
+}
+
+func TestIncludesDisabled(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"secret.ini": "[main]\ntoken = secret\n",
+	})
+	secretPath := filepath.Join(dir, "secret.ini")
+
+	// a source that is not a file never opens the files it includes, even
+	// with the Includes option.
+	_, err := strongParser.ParseStringWithOption("include = "+secretPath+"\n", includeOptions())
+	if err == nil || errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got %v for an include line in a string", err)
+	}
+
+	source := "[include]\nsecret = " + secretPath + "\n\n[main]\nport = 80\n"
+	p, err := strongParser.ParseStringWithOption(source, includeOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token, err := p.Get("main", "token"); err == nil {
+		t.Errorf("ParseString read the included file: token = %q", token)
+	}
+
+	// without the Includes option, [include] is a normal section.
+	path := filepath.Join(dir, "config.ini")
+	if err = os.WriteFile(path, []byte("[include]\nfoo = bar\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p, err = strongParser.ParseWithOption(path, noEnvOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if foo, _ := p.Get("include", "foo"); foo != "bar" {
+		t.Errorf("[include] is not a normal section: foo = %q", foo)
+	}
+}
+
+func TestIncludeCycle(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"a.ini": "include = b.ini\n[main]\na = 1\n",
+		"b.ini": "include = a.ini\n[main]\nb = 1\n",
+	})
+
+	_, err := strongParser.ParseWithOption(filepath.Join(dir, "a.ini"), includeOptions())
+	if !errors.Is(err, strongParser.ErrIncludeCycle) {
+		t.Fatalf("expected ErrIncludeCycle, got %v", err)
+	}
+}
+
+func TestParseLayersAndProfiles(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"config.ini":            "[main]\nport = 80\nhost = localhost\ndebug = true\n",
+		"config.production.ini": "[main]\ndebug = false\n",
+		"local.ini":             "[main]\nport = 8080\n",
+	})
+	configPath := filepath.Join(dir, "config.ini")
+	localPath := filepath.Join(dir, "local.ini")
+
+	p, err := strongParser.ParseLayers(configPath, localPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if port, _ := p.Get("main", "port"); port != "8080" {
+		t.Errorf("port is %q, the later layer must override it", port)
+	}
+	if host, _ := p.Get("main", "host"); host != "localhost" {
+		t.Errorf("host is %q, want the value of the first layer", host)
+	}
+
+	p, err = strongParser.ParseWithOption(configPath, &strongParser.ConfigParserOptions{
+		Profile: "production",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if debug, _ := p.Get("main", "debug"); debug != "false" {
+		t.Errorf("debug is %q, the profile overlay was not applied", debug)
+	}
+
+	t.Setenv("CONFIG_PROFILE", "production")
+	type config struct {
+		Port  int
+		Debug bool
+	}
+
+	// the profile is only read from the environment if ProfileEnv is set.
+	value := &config{}
+	if err = strongParser.ParseConfig(value, configPath); err != nil {
+		t.Fatal(err)
+	}
+	if !value.Debug {
+		t.Errorf("the profile was read from the environment by default: %+v", value)
+	}
+
+	opt := noEnvOptions()
+	opt.ProfileEnv = "CONFIG_PROFILE"
+	value = &config{}
+	if err = strongParser.ParseConfigWithOption(value, configPath, opt); err != nil {
+		t.Fatal(err)
+	}
+	if value.Debug || value.Port != 80 {
+		t.Errorf("the profile from the environment was not applied: %+v", value)
+	}
+}
+
+func TestIncludeErrorsWithReadEnv(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"a.ini":       "include = b.ini\n[main]\nport = 2\n",
+		"b.ini":       "include = a.ini\n",
+		"missing.ini": "include = nothing.ini\n[main]\nport = 3\n",
+	})
+
+	opt := includeOptions()
+	opt.ReadEnv = true
+
+	config := &defaultsConfig{}
+	err := strongParser.ParseConfigWithOption(config, filepath.Join(dir, "a.ini"), opt)
+	if !errors.Is(err, strongParser.ErrIncludeCycle) {
+		t.Errorf("got %v (port %d) for an include cycle", err, config.Port)
+	}
+
+	config = &defaultsConfig{}
+	err = strongParser.ParseConfigWithOption(config, filepath.Join(dir, "missing.ini"), opt)
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got %v (port %d) for a missing include", err, config.Port)
+	}
+
+	// only a missing config file itself is replaced by the environment.
+	config = &defaultsConfig{}
+	err = strongParser.ParseConfigWithOption(config, filepath.Join(dir, "nothing.ini"), opt)
+	if err != nil || config.Port != 8080 {
+		t.Errorf("got %v (port %d) for a missing config file", err, config.Port)
+	}
+}
@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ALiwoto/ssg/ssg/strongParser"
+)
+
+const interpolationConfig = `
+[DEFAULT]
+home = /srv/bot
+
+[main]
+data_dir = %(home)s/data
+log_file = %(data_dir)s/bot.log
+db_url = ${database:url}
+api_url = ${SSG_TEST_API_URL:-https://${main:host}/api}
+host = example.com
+price = 100%% $$5
+token = ${SSG_TEST_TOKEN}
+
+[database]
+url = postgres://${main:host}/db
+
+[cycle]
+a = ${cycle:b}
+b = %(a)s
+`
+
+func newInterpolatingParser(t *testing.T) *strongParser.ConfigParser {
+	t.Helper()
+
+	p, err := strongParser.ParseString(interpolationConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p.SetOptions(&strongParser.ConfigParserOptions{
+		MainSectionName: strongParser.DefaultMainSection,
+		Interpolate:     true,
+	})
+	return p
+}
+
+func TestInterpolation(t *testing.T) {
+	t.Setenv("SSG_TEST_TOKEN", "12345:abcd")
+	p := newInterpolatingParser(t)
+
+	expected := map[string]string{
+		"data_dir": "/srv/bot/data",
+		"log_file": "/srv/bot/data/bot.log",
+		"db_url":   "postgres://example.com/db",
+		"api_url":  "https://example.com/api",
+		"price":    "100% $5",
+		"token":    "12345:abcd",
+	}
+	for key, want := range expected {
+		got, err := p.Get("main", key)
+		if err != nil {
+			t.Errorf("Get(main, %s) returned %v", key, err)
+		} else if got != want {
+			t.Errorf("Get(main, %s) = %q, want %q", key, got, want)
+		}
+	}
+
+	t.Setenv("SSG_TEST_API_URL", "https://env.example.com")
+	if got, _ := p.Get("main", "api_url"); got != "https://env.example.com" {
+		t.Errorf("the environment variable didn't take precedence over the fallback: %q", got)
+	}
+
+	if raw, _ := p.GetRaw("main", "log_file"); raw != "%(data_dir)s/bot.log" {
+		t.Errorf("GetRaw returned %q, want the value as is", raw)
+	}
+}
+
+func TestInterpolationIsOffByDefault(t *testing.T) {
+	p, err := strongParser.ParseString(interpolationConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value, _ := p.Get("main", "data_dir"); value != "%(home)s/data" {
+		t.Errorf("value was interpolated without the option: %q", value)
+	}
+}
+
+func TestInterpolationCycle(t *testing.T) {
+	p := newInterpolatingParser(t)
+
+	_, err := p.Get("cycle", "a")
+	if !errors.Is(err, strongParser.ErrInterpolationCycle) {
+		t.Fatalf("expected ErrInterpolationCycle, got %v", err)
+	}
+
+	type cycleConfig struct {
+		A string `section:"cycle"`
+	}
+	err = strongParser.ParseStringConfigWithOption(&cycleConfig{}, interpolationConfig, &strongParser.ConfigParserOptions{
+		MainSectionName: strongParser.DefaultMainSection,
+		Interpolate:     true,
+	})
+	if !errors.Is(err, strongParser.ErrInterpolationCycle) {
+		t.Fatalf("ParseConfig didn't report the cycle: %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package tests
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ALiwoto/ssg/ssg/strongParser"
+)
+
+type botConfig struct {
+	Token       string
+	OwnerIds    []int64
+	Replica     replicaConfig
+	sectionName string
+}
+
+func (b *botConfig) SetSectionName(name string) {
+	b.sectionName = name
+}
+
+func (b *botConfig) GetSectionName() string {
+	return b.sectionName
+}
+
+type mapConfig struct {
+	Aliases  map[string]string `section:"aliases"`
+	Limits   map[string]int
+	Timeouts map[string]time.Duration
+	Bots     map[string]*botConfig `section:"bot"`
+	Plugins  map[string]redisConfig
+	Missing  map[string]string
+}
+
+const mapConfigValue = `
+[aliases]
+h = help
+St = start
+
+[limits]
+messages = 30
+Files = 5
+
+[timeouts]
+api = 30s
+long_polling = 1d
+
+[bot.alpha]
+token = 1:alpha
+owner_ids = 1, 2
+
+[bot.alpha.replica]
+host = alpha.local
+
+[bot.beta]
+token = 2:beta
+
+[plugins.cache]
+address = localhost:6379
+`
+
+func TestParseMapFields(t *testing.T) {
+	value := &mapConfig{}
+	err := strongParser.ParseStringConfigWithOption(value, mapConfigValue, noEnvOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(value.Aliases, map[string]string{"h": "help", "St": "start"}) {
+		t.Errorf("Aliases is %v", value.Aliases)
+	}
+	if !reflect.DeepEqual(value.Limits, map[string]int{"messages": 30, "Files": 5}) {
+		t.Errorf("Limits is %v", value.Limits)
+	}
+	if value.Timeouts["long_polling"] != 24*time.Hour {
+		t.Errorf("Timeouts is %v", value.Timeouts)
+	}
+	if value.Missing != nil {
+		t.Errorf("Missing was allocated without a section: %v", value.Missing)
+	}
+
+	if len(value.Bots) != 2 {
+		t.Fatalf("Bots has %d entries, want 2: %v", len(value.Bots), value.Bots)
+	}
+	alpha := value.Bots["alpha"]
+	if alpha == nil || alpha.Token != "1:alpha" || len(alpha.OwnerIds) != 2 {
+		t.Errorf("Bots[alpha] is %+v", alpha)
+	} else if alpha.Replica.Host != "alpha.local" || alpha.sectionName != "bot.alpha" {
+		t.Errorf("Bots[alpha] nested section or name wasn't set: %+v", alpha)
+	}
+	if beta := value.Bots["beta"]; beta == nil || beta.Token != "2:beta" {
+		t.Errorf("Bots[beta] is %+v", beta)
+	}
+	if value.Plugins["cache"].Address != "localhost:6379" {
+		t.Errorf("Plugins is %v", value.Plugins)
+	}
+}
+
+func TestParseMapFieldInvalidValue(t *testing.T) {
+	value := &mapConfig{}
+	err := strongParser.ParseStringConfigWithOption(value, "[limits]\nmessages = many\n", noEnvOptions())
+	if err == nil || !strings.Contains(err.Error(), "key 'messages'") {
+		t.Fatalf("an invalid map value was not reported: %v", err)
+	}
+}
+
+func TestMarshalMapFields(t *testing.T) {
+	value := &mapConfig{}
+	err := strongParser.ParseStringConfigWithOption(value, mapConfigValue, noEnvOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := strongParser.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed := &mapConfig{}
+	err = strongParser.ParseStringConfigWithOption(parsed, string(data), noEnvOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(value, parsed) {
+		t.Fatalf("Marshal output didn't round-trip:\n%s", data)
+	}
+}
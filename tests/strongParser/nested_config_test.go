@@ -0,0 +1,179 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/ALiwoto/ssg/ssg/strongParser"
+)
+
+type replicaConfig struct {
+	Host string
+	Port int
+}
+
+type dbConfig struct {
+	Url     string
+	MaxConn int `key:"max_connections"`
+	Replica replicaConfig
+	Backup  *replicaConfig `section:"backup_replica"`
+}
+
+type redisConfig struct {
+	Address string
+}
+
+type nestedConfig struct {
+	Token    string
+	Database dbConfig
+	Redis    *redisConfig `section:"cache"`
+	Missing  *redisConfig
+}
+
+const nestedConfigValue = `
+[main]
+token = 12345:abcd
+
+[database]
+url = postgres://localhost/db
+max_connections = 10
+
+[database.replica]
+host = replica.local
+port = 5433
+
+[database.backup_replica]
+host = backup.local
+
+[cache]
+address = localhost:6379
+`
+
+func TestParseNestedStructs(t *testing.T) {
+	value := &nestedConfig{}
+	err := strongParser.ParseStringConfig(value, nestedConfigValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value.Token != "12345:abcd" {
+		t.Errorf("Token is %q", value.Token)
+	}
+	if value.Database.Url != "postgres://localhost/db" || value.Database.MaxConn != 10 {
+		t.Errorf("Database was not parsed from its own section: %+v", value.Database)
+	}
+	if value.Database.Replica.Host != "replica.local" || value.Database.Replica.Port != 5433 {
+		t.Errorf("Database.Replica was not parsed from [database.replica]: %+v", value.Database.Replica)
+	}
+	if value.Database.Backup == nil || value.Database.Backup.Host != "backup.local" {
+		t.Errorf("Database.Backup was not parsed from [database.backup_replica]: %+v", value.Database.Backup)
+	}
+	if value.Redis == nil || value.Redis.Address != "localhost:6379" {
+		t.Errorf("Redis was not parsed from [cache]: %+v", value.Redis)
+	}
+	if value.Missing != nil {
+		t.Errorf("Missing was allocated without a section: %+v", value.Missing)
+	}
+}
+
+func TestParseNestedStructsFromEnv(t *testing.T) {
+	t.Setenv("DATABASE_REPLICA_HOST", "env.replica.local")
+	t.Setenv("MISSING_ADDRESS", "env.missing.local")
+
+	value := &nestedConfig{}
+	err := strongParser.ParseStringConfig(value, "[main]\ntoken = abc\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value.Database.Replica.Host != "env.replica.local" {
+		t.Errorf("Database.Replica.Host is %q, want env.replica.local", value.Database.Replica.Host)
+	}
+	if value.Missing == nil || value.Missing.Address != "env.missing.local" {
+		t.Errorf("Missing was not filled from the environment: %+v", value.Missing)
+	}
+}
+
+type embeddedBase struct {
+	Name  string
+	Debug bool
+}
+
+// Replica and Cache are exported, since a nil embedded pointer can only be
+// allocated (and a tagged embedded struct only read) for exported types.
+type Replica replicaConfig
+
+type Cache redisConfig
+
+type embeddedConfig struct {
+	embeddedBase
+	*Replica
+	Cache redisConfig `section:"cache"`
+	Token string
+}
+
+type taggedEmbeddedConfig struct {
+	Cache `section:"cache"`
+	Token string
+}
+
+func TestParseEmbeddedStructs(t *testing.T) {
+	const config = `
+[main]
+token = 12345:abcd
+name = app
+debug = true
+host = db.local
+port = 5432
+
+[cache]
+address = localhost:6379
+`
+	value := &embeddedConfig{}
+	err := strongParser.ParseStringConfigWithOption(value, config, noEnvOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value.Name != "app" || !value.Debug || value.Token != "12345:abcd" {
+		t.Errorf("embedded fields were not read from [main]: %+v", value)
+	}
+	if value.Replica == nil || value.Host != "db.local" || value.Port != 5432 {
+		t.Errorf("embedded pointer was not read from [main]: %+v", value.Replica)
+	}
+	if value.Cache.Address != "localhost:6379" {
+		t.Errorf("Cache is %+v", value.Cache)
+	}
+
+	tagged := &taggedEmbeddedConfig{}
+	err = strongParser.ParseStringConfigWithOption(tagged, config, noEnvOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tagged.Address != "localhost:6379" || tagged.Token != "12345:abcd" {
+		t.Errorf("embedded struct with a section tag was not read from it: %+v", tagged)
+	}
+}
+
+func TestMarshalEmbeddedStructs(t *testing.T) {
+	value := &embeddedConfig{
+		embeddedBase: embeddedBase{Name: "app"},
+		Replica:      &Replica{Host: "db.local"},
+		Token:        "abcd",
+	}
+	data, err := strongParser.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed := &embeddedConfig{}
+	err = strongParser.ParseStringConfigWithOption(parsed, string(data), noEnvOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if parsed.Name != "app" || parsed.Token != "abcd" ||
+		parsed.Replica == nil || parsed.Host != "db.local" {
+		t.Errorf("embedded fields did not round-trip through:\n%s", data)
+	}
+}
@@ -0,0 +1,84 @@
+package tests
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ALiwoto/ssg/ssg/strongParser"
+)
+
+func TestParseErrors(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"config.ini": "token = abc\n" +
+			"[main]\n" +
+			"port = 80\n" +
+			"  this line is wrong\n" +
+			"; a comment\n" +
+			"[database\n" +
+			"[main]\n" +
+			"owner = 1\n",
+	})
+	path := filepath.Join(dir, "config.ini")
+
+	_, err := strongParser.Parse(path)
+
+	var parseErrs strongParser.ParseErrors
+	if !errors.As(err, &parseErrs) {
+		t.Fatalf("got %v, want ParseErrors", err)
+	}
+
+	expected := []strongParser.ParseError{
+		{File: path, Line: 1, Column: 1, Snippet: "token = abc", Kind: strongParser.ParseErrorMissingSection},
+		{File: path, Line: 4, Column: 3, Snippet: "this line is wrong", Kind: strongParser.ParseErrorMalformedLine},
+		{File: path, Line: 6, Column: 10, Snippet: "[database", Kind: strongParser.ParseErrorUnterminatedSection},
+		{File: path, Line: 7, Column: 1, Snippet: "[main]", Kind: strongParser.ParseErrorDuplicateSection},
+	}
+	if len(parseErrs) != len(expected) {
+		t.Fatalf("got %d errors, want %d:\n%v", len(parseErrs), len(expected), err)
+	}
+	for i, want := range expected {
+		if *parseErrs[i] != want {
+			t.Errorf("error %d = %+v, want %+v", i, *parseErrs[i], want)
+		}
+	}
+
+	// a single error can be extracted as well.
+	var parseErr *strongParser.ParseError
+	if !errors.As(err, &parseErr) || parseErr.Line != 1 {
+		t.Errorf("errors.As(*ParseError) = %+v", parseErr)
+	}
+
+	want := path + ":4:3: malformed line: this line is wrong"
+	if !strings.Contains(err.Error(), want) {
+		t.Errorf("error message %q doesn't contain %q", err.Error(), want)
+	}
+
+	// syntax errors are not ignored, even if the environment may be read.
+	opt := &strongParser.ConfigParserOptions{ReadEnv: true}
+	if err = strongParser.ParseConfigWithOption(&nestedConfig{}, path, opt); !errors.As(err, &parseErrs) {
+		t.Errorf("ParseConfigWithOption returned %v, want ParseErrors", err)
+	}
+}
+
+func TestParseErrorUnterminatedValue(t *testing.T) {
+	opt := noEnvOptions()
+	opt.QuotedValues = true
+
+	_, err := strongParser.ParseStringWithOption("[main]\nbody = \"\"\"\nfirst\nsecond\n", opt)
+
+	var parseErr *strongParser.ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("got %v, want a ParseError", err)
+	}
+
+	if parseErr.Kind != strongParser.ParseErrorUnterminatedValue || parseErr.Line != 2 ||
+		parseErr.Column != 8 || parseErr.File != "" {
+		t.Errorf("got %+v", parseErr)
+	}
+
+	if err.Error() != `strongParser: 2:8: unterminated triple-quoted value: body = """` {
+		t.Errorf("got %q", err.Error())
+	}
+}
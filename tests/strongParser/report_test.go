@@ -0,0 +1,91 @@
+package tests
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ALiwoto/ssg/ssg/strongParser"
+)
+
+type reportConfig struct {
+	Token   string `secret:"true"`
+	Port    int    `default:"80"`
+	Owner   string `env:"REPORT_TEST_OWNER"`
+	Admins  []int64
+	Limits  map[string]int
+	Servers []serverConfig `section:"server"`
+	Unset   string
+}
+
+const reportConfigValue = `[main]
+token = 12345:abcd
+
+admins = 1, 2
+
+[limits]
+ban = 5
+
+[server.alpha]
+host = alpha.local
+`
+
+func TestParseConfigWithReport(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{"config.ini": reportConfigValue})
+	filename := filepath.Join(dir, "config.ini")
+	t.Setenv("REPORT_TEST_OWNER", "me")
+
+	report, err := strongParser.ParseConfigWithReport(&reportConfig{}, filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]strongParser.ValueSource{
+		"Token":           {Kind: strongParser.SourceFile, File: filename, Line: 2, Value: "12345:abcd"},
+		"Port":            {Kind: strongParser.SourceDefault, Value: "80"},
+		"Owner":           {Kind: strongParser.SourceEnv, Env: "REPORT_TEST_OWNER", Value: "me"},
+		"Admins":          {Kind: strongParser.SourceFile, File: filename, Line: 4, Value: "1, 2"},
+		"Limits[ban]":     {Kind: strongParser.SourceFile, File: filename, Line: 7, Value: "5"},
+		"Servers[0].Host": {Kind: strongParser.SourceFile, File: filename, Line: 10, Value: "alpha.local"},
+		"Servers[0].Port": {Kind: strongParser.SourceDefault, Value: "80"},
+	}
+
+	if len(report) != len(expected) {
+		t.Errorf("got %d sources, want %d:\n%s", len(report), len(expected), report.Explain())
+	}
+
+	for path, want := range expected {
+		got := report[path]
+		if got == nil || got.Kind != want.Kind || got.File != want.File || got.Line != want.Line ||
+			got.Env != want.Env || got.Value != want.Value {
+			t.Errorf("%s: got %+v, want %+v", path, got, want)
+		}
+	}
+
+	explained := report.Explain()
+	if strings.Contains(explained, "12345:abcd") ||
+		!strings.Contains(explained, "Owner = \"me\" (env REPORT_TEST_OWNER)\n") ||
+		!strings.Contains(explained, "Port = \"80\" (default)\n") ||
+		!strings.Contains(explained, "Token = \"******\" ("+filename+":2)\n") {
+		t.Errorf("unexpected explanation:\n%s", explained)
+	}
+}
+
+func TestParseConfigWithFlagsAndReport(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{"config.ini": reportConfigValue})
+
+	report, err := strongParser.ParseConfigWithFlagsAndReport(&reportConfig{},
+		filepath.Join(dir, "config.ini"), []string{"--main.port", "8080"}, noEnvOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	port := report["Port"]
+	if port == nil || port.Kind != strongParser.SourceFlag || port.Flag != "--main.port" || port.Value != "8080" {
+		t.Errorf("got %+v", port)
+	}
+
+	if owner := report["Owner"]; owner != nil {
+		t.Errorf("got %+v for a field without a value", owner)
+	}
+}
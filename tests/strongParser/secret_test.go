@@ -0,0 +1,136 @@
+package tests
+
+import (
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ALiwoto/ssg/ssg/strongParser"
+)
+
+type secretConfig struct {
+	Token    string `secret:"true"`
+	Password string `secret:"true"`
+	ApiKey   string `env:"SECRET_TEST_API_KEY" secret:"true"`
+	Owner    string
+}
+
+func secretOptions(key []byte) *strongParser.ConfigParserOptions {
+	opt := noEnvOptions()
+	opt.ResolveSecrets = true
+	opt.SecretKey = key
+	return opt
+}
+
+func TestSecretValues(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	encrypted, err := strongParser.EncryptValue("hunter2", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(encrypted, "enc:") || strings.Contains(encrypted, "hunter2") {
+		t.Fatalf("EncryptValue returned %q", encrypted)
+	}
+
+	dir := writeConfigFiles(t, map[string]string{
+		"token":   "file-token\n",
+		"api_key": "file-api-key\n",
+	})
+	t.Setenv("SECRET_TEST_API_KEY_FILE", filepath.Join(dir, "api_key"))
+
+	value := "[main]\ntoken = file:" + filepath.Join(dir, "token") +
+		"\npassword = " + encrypted + "\nowner = someone\n"
+
+	config := &secretConfig{}
+	err = strongParser.ParseStringConfigWithOption(config, value, secretOptions(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := secretConfig{
+		Token:    "file-token",
+		Password: "hunter2",
+		ApiKey:   "file-api-key",
+		Owner:    "someone",
+	}
+	if *config != expected {
+		t.Errorf("got %+v, want %+v", *config, expected)
+	}
+
+	// the key can be given through the environment.
+	t.Setenv(strongParser.DefaultSecretKeyEnv, base64.StdEncoding.EncodeToString(key))
+	config = &secretConfig{}
+	if err = strongParser.ParseStringConfigWithOption(config, value, secretOptions(nil)); err != nil {
+		t.Fatal(err)
+	}
+	if config.Password != "hunter2" {
+		t.Errorf("got password %q with the key from the environment", config.Password)
+	}
+
+	// secrets are not resolved unless enabled.
+	config = &secretConfig{}
+	if err = strongParser.ParseStringConfigWithOption(config, value, noEnvOptions()); err != nil {
+		t.Fatal(err)
+	}
+	if config.Password != encrypted {
+		t.Errorf("got password %q without ResolveSecrets", config.Password)
+	}
+}
+
+func TestSecretValueErrors(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	encrypted, err := strongParser.EncryptValue("hunter2", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	os.Unsetenv(strongParser.DefaultSecretKeyEnv)
+	value := "[main]\npassword = " + encrypted + "\n"
+	err = strongParser.ParseStringConfigWithOption(&secretConfig{}, value, secretOptions(nil))
+	if !errors.Is(err, strongParser.ErrNoSecretKey) {
+		t.Errorf("got %v, want ErrNoSecretKey", err)
+	}
+
+	wrongKey := []byte("fedcba9876543210")
+	err = strongParser.ParseStringConfigWithOption(&secretConfig{}, value, secretOptions(wrongKey))
+	var secretErr *strongParser.SecretError
+	if !errors.Is(err, strongParser.ErrInvalidSecret) || !errors.As(err, &secretErr) ||
+		secretErr.Option != "password" {
+		t.Errorf("got %v, want a SecretError with ErrInvalidSecret", err)
+	}
+
+	value = "[main]\ntoken = file:" + filepath.Join(t.TempDir(), "missing") + "\n"
+	err = strongParser.ParseStringConfigWithOption(&secretConfig{}, value, secretOptions(key))
+	if !errors.Is(err, os.ErrNotExist) || !errors.As(err, &secretErr) || secretErr.Option != "token" {
+		t.Errorf("got %v, want a SecretError for a missing file", err)
+	}
+
+	if _, err = strongParser.EncryptValue("x", []byte("short")); !errors.Is(err, strongParser.ErrInvalidSecretKey) {
+		t.Errorf("got %v, want ErrInvalidSecretKey", err)
+	}
+}
+
+func TestDumpMasksSecrets(t *testing.T) {
+	config := &secretConfig{Token: "t0ken", Password: "hunter2", Owner: "me"}
+
+	dump, err := strongParser.Dump(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(dump, "t0ken") || strings.Contains(dump, "hunter2") ||
+		!strings.Contains(dump, "token = ******") || !strings.Contains(dump, "owner = me") {
+		t.Errorf("Dump returned:\n%s", dump)
+	}
+
+	// Marshal writes the actual values back.
+	b, err := strongParser.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "password = hunter2") {
+		t.Errorf("Marshal returned:\n%s", b)
+	}
+}
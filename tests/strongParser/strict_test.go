@@ -0,0 +1,160 @@
+package tests
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/ALiwoto/ssg/ssg/strongParser"
+)
+
+type strictConfig struct {
+	Token   string
+	Port    int
+	Ratios  []float64
+	Limits  map[string]int `section:"limits"`
+	Backend struct {
+		Address string
+		Timeout int
+	}
+}
+
+func strictOptions() *strongParser.ConfigParserOptions {
+	opt := noEnvOptions()
+	opt.Strict = true
+	return opt
+}
+
+// strictCase is a problem expected to be reported by the Strict option.
+type strictCase struct {
+	err        error
+	section    string
+	key        string
+	line       int
+	suggestion string
+}
+
+func checkStrictErrors(t *testing.T, err error, expected []strictCase) {
+	t.Helper()
+
+	var violations strongParser.ValidationErrors
+	if !errors.As(err, &violations) {
+		t.Fatalf("got %v, want ValidationErrors", err)
+	}
+
+	if len(violations) != len(expected) {
+		t.Fatalf("got %d errors, want %d:\n%v", len(violations), len(expected), err)
+	}
+
+	for i, want := range expected {
+		got := violations[i]
+		if !errors.Is(got, want.err) || got.Section != want.section || got.Key != want.key ||
+			got.Line != want.line || got.Suggestion != want.suggestion {
+			t.Errorf("error %d = %+v, want %+v", i, got, want)
+		}
+	}
+}
+
+func TestStrictMode(t *testing.T) {
+	const value = `[main]
+token = abc
+port = abc
+ratios = 0.5, x
+prot = 80
+token = def
+
+[limits]
+users = many
+
+[backend]
+adress = localhost
+timeout = 5
+
+[unrelated]
+anything = goes
+`
+
+	err := strongParser.ParseStringConfigWithOption(&strictConfig{}, value, strictOptions())
+	checkStrictErrors(t, err, []strictCase{
+		{strongParser.ErrDuplicateKey, "main", "token", 6, ""},
+		{strongParser.ErrTypeMismatch, "main", "port", 3, ""},
+		{strongParser.ErrTypeMismatch, "main", "ratios", 4, ""},
+		{strongParser.ErrTypeMismatch, "limits", "users", 9, ""},
+		{strongParser.ErrUnknownKey, "main", "prot", 5, "port"},
+		{strongParser.ErrUnknownKey, "backend", "adress", 12, "address"},
+	})
+
+	// none of these are reported without the Strict option.
+	config := &strictConfig{}
+	err = strongParser.ParseStringConfigWithOption(config, value, noEnvOptions())
+	var violations strongParser.ValidationErrors
+	if !errors.As(err, &violations) || len(violations) != 1 || violations[0].Key != "users" {
+		t.Fatalf("got %v, want a single error for limits.users", err)
+	}
+	if config.Port != 0 || len(config.Ratios) != 1 || config.Backend.Timeout != 5 {
+		t.Errorf("got %+v", config)
+	}
+}
+
+func TestStrictModeKeyOutsideSection(t *testing.T) {
+	const value = "token = abc\n\n[main]\nport = 80\n"
+
+	_, err := strongParser.ParseString(value)
+	if err == nil {
+		t.Fatal("a key before the first section was accepted")
+	}
+
+	config := &strictConfig{}
+	err = strongParser.ParseStringConfigWithOption(config, value, strictOptions())
+	checkStrictErrors(t, err, []strictCase{
+		{strongParser.ErrKeyOutsideSection, "", "token", 1, ""},
+	})
+
+	if violations := err.(strongParser.ValidationErrors); violations[0].Error() !=
+		"line 1: key 'token': key is outside of any section" {
+		t.Errorf("got %q", violations[0].Error())
+	}
+}
+
+func TestStrictModeEnv(t *testing.T) {
+	t.Setenv("STRICT_TEST_PORT", "eighty")
+
+	type envConfig struct {
+		Port int `env:"STRICT_TEST_PORT"`
+	}
+
+	err := strongParser.ParseStringConfigWithOption(&envConfig{}, "[main]\n", strictOptions())
+	checkStrictErrors(t, err, []strictCase{
+		{strongParser.ErrTypeMismatch, "main", "port", 0, ""},
+	})
+
+	os.Unsetenv("STRICT_TEST_PORT")
+	if err = strongParser.ParseStringConfigWithOption(&envConfig{}, "[main]\n", strictOptions()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIntegerOverflow(t *testing.T) {
+	type overflowConfig struct {
+		Port    int16
+		Retries uint8 `default:"300"`
+		Workers *uint8
+	}
+
+	const value = "[main]\nport = 99999\nworkers = 256\n"
+
+	config := &overflowConfig{}
+	err := strongParser.ParseStringConfigWithOption(config, value, noEnvOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Port != 0 || config.Retries != 0 || config.Workers == nil || *config.Workers != 0 {
+		t.Errorf("overflowing values were set: %+v", config)
+	}
+
+	err = strongParser.ParseStringConfigWithOption(&overflowConfig{}, value, strictOptions())
+	checkStrictErrors(t, err, []strictCase{
+		{strongParser.ErrTypeMismatch, "main", "port", 2, ""},
+		{strongParser.ErrTypeMismatch, "main", "workers", 3, ""},
+	})
+}
@@ -0,0 +1,131 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ALiwoto/ssg/ssg/strongParser"
+)
+
+type serverConfig struct {
+	Host string `required:"true"`
+	Port int    `default:"80"`
+	Tls  *struct {
+		Cert string
+	}
+
+	name string
+}
+
+func (s *serverConfig) SetSectionName(name string) {
+	s.name = name
+}
+
+func (s *serverConfig) GetSectionName() string {
+	return s.name
+}
+
+type backupConfig struct {
+	Path string
+}
+
+type sliceConfig struct {
+	Token   string
+	Servers []serverConfig  `section:"server"`
+	Workers []*serverConfig `section:"worker-*"`
+	Backup  *backupConfig
+	Missing []serverConfig
+}
+
+const sliceConfigValue = `
+[main]
+token = 12345:abcd
+
+[server:1]
+host = first.local
+
+[server.alpha]
+host = alpha.local
+port = 8080
+
+[server.alpha.tls]
+cert = alpha.pem
+
+[worker-a]
+host = worker-a.local
+
+[worker-b]
+host = worker-b.local
+
+[backup]
+path = /var/backups
+`
+
+func TestStructSlices(t *testing.T) {
+	config := &sliceConfig{}
+	err := strongParser.ParseStringConfigWithOption(config, sliceConfigValue, noEnvOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(config.Servers) != 2 {
+		t.Fatalf("got %d servers, want 2: %+v", len(config.Servers), config.Servers)
+	}
+
+	first, alpha := config.Servers[0], config.Servers[1]
+	if first.GetSectionName() != "server:1" || first.Host != "first.local" || first.Port != 80 ||
+		first.Tls != nil {
+		t.Errorf("got %+v", first)
+	}
+
+	if alpha.GetSectionName() != "server.alpha" || alpha.Host != "alpha.local" || alpha.Port != 8080 ||
+		alpha.Tls == nil || alpha.Tls.Cert != "alpha.pem" {
+		t.Errorf("got %+v", alpha)
+	}
+
+	if len(config.Workers) != 2 || config.Workers[0].GetSectionName() != "worker-a" ||
+		config.Workers[1].Host != "worker-b.local" {
+		t.Errorf("got workers %+v", config.Workers)
+	}
+
+	if config.Backup == nil || config.Backup.Path != "/var/backups" || config.Missing != nil {
+		t.Errorf("got %+v", config)
+	}
+
+	// the elements are validated with their own section.
+	err = strongParser.ParseStringConfigWithOption(&sliceConfig{}, "[server.beta]\nport = 1\n", noEnvOptions())
+	if err == nil || !strings.Contains(err.Error(), "server.beta") {
+		t.Errorf("got error %v for a server without a host", err)
+	}
+}
+
+func TestMarshalStructSlices(t *testing.T) {
+	config := &sliceConfig{}
+	err := strongParser.ParseStringConfigWithOption(config, sliceConfigValue, noEnvOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := strongParser.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed := &sliceConfig{}
+	err = strongParser.ParseStringConfigWithOption(parsed, string(b), noEnvOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(parsed.Servers) != 2 || parsed.Servers[1].Tls == nil || parsed.Servers[1].Tls.Cert != "alpha.pem" ||
+		len(parsed.Workers) != 2 {
+		t.Errorf("got %+v from:\n%s", parsed, b)
+	}
+
+	template := strongParser.GenerateTemplate(&sliceConfig{})
+	for _, expected := range []string{"[server.1]\n", "[worker-1]\n", "one section matching [worker-*]"} {
+		if !strings.Contains(template, expected) {
+			t.Errorf("template doesn't contain %q:\n%s", expected, template)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package tests
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ALiwoto/ssg/ssg/strongParser"
+)
+
+type templateBot struct {
+	Token string `required:"true"`
+}
+
+type templateConfig struct {
+	Token    string        `desc:"Token of the bot." required:"true" secret:"true"`
+	Mode     string        `oneof:"dev prod" default:"dev"`
+	Timeout  time.Duration `default:"5s"`
+	Admins   []int64       `env:"BOT_ADMINS" default:"1, 2"`
+	Database struct {
+		Url     string `url:"true"`
+		MaxConn int    `key:"max_connections" default:"10" range:"1-100"`
+	}
+	Limits map[string]int `desc:"Limits by command."`
+	Bots   map[string]*templateBot
+}
+
+func TestGenerateTemplate(t *testing.T) {
+	template := strongParser.GenerateTemplate((*templateConfig)(nil))
+
+	for _, expected := range []string{
+		"[main]\n# Token of the bot.\n# type: string; required; secret\n# env: MAIN_TOKEN, token, TOKEN\ntoken =\n",
+		"# type: string; one of: dev, prod\n# env: MAIN_MODE, mode, MODE\nmode = dev\n",
+		"# type: []int64\n# env: BOT_ADMINS, MAIN_ADMINS, admins, ADMINS\nadmins = 1, 2\n",
+		"[database]\n# type: string; absolute URL\n",
+		"# type: int; range: 1-100\n# env: DATABASE_MAX_CONNECTIONS, max_connections, MAX_CONNECTIONS\nmax_connections = 10\n",
+		"[limits]\n# Limits by command.\n# one option per entry of type int\n# name = value\n",
+		"[bots.example]\n# an example entry; one [bots.<name>] section per entry\n",
+	} {
+		if !strings.Contains(template, expected) {
+			t.Errorf("the template doesn't contain %q:\n%s", expected, template)
+		}
+	}
+
+	// the template is a valid config file, apart from the missing values.
+	config := &templateConfig{}
+	err := strongParser.ParseStringConfigWithOption(config, template, noEnvOptions())
+	if err == nil || !strings.Contains(err.Error(), "key 'token': is required") {
+		t.Fatalf("got %v, want the required tokens to be reported", err)
+	}
+
+	template = strings.ReplaceAll(template, "token =\n", "token = abc\n")
+	config = &templateConfig{}
+	if err = strongParser.ParseStringConfigWithOption(config, template, noEnvOptions()); err != nil {
+		t.Fatal(err)
+	}
+	if config.Mode != "dev" || config.Timeout != 5*time.Second || config.Database.MaxConn != 10 ||
+		len(config.Admins) != 2 || config.Bots["example"] == nil {
+		t.Errorf("got %+v", config)
+	}
+}
+
+func TestGenerateJSONSchema(t *testing.T) {
+	b, err := strongParser.GenerateJSONSchema(templateConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var schema map[string]any
+	if err = json.Unmarshal(b, &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	get := func(path ...string) any {
+		var current any = schema
+		for _, name := range path {
+			object, ok := current.(map[string]any)
+			if !ok {
+				return nil
+			}
+			current = object[name]
+		}
+		return current
+	}
+
+	expected := map[string]any{
+		"properties.main.properties.token.description":               "Token of the bot.",
+		"properties.main.properties.token.writeOnly":                 true,
+		"properties.main.properties.mode.enum":                       []any{"dev", "prod"},
+		"properties.main.properties.timeout.default":                 "5s",
+		"properties.main.properties.admins.type":                     "array",
+		"properties.main.properties.admins.items.type":               "integer",
+		"properties.main.properties.admins.default":                  []any{1.0, 2.0},
+		"properties.main.required":                                   []any{"token"},
+		"properties.database.properties.url.format":                  "uri",
+		"properties.database.properties.max_connections.minimum":     1.0,
+		"properties.database.properties.max_connections.maximum":     100.0,
+		"properties.limits.additionalProperties.type":                "integer",
+		"properties.bots.additionalProperties.properties.token.type": "string",
+		"properties.bots.additionalProperties.required":              []any{"token"},
+	}
+	for path, want := range expected {
+		got, _ := json.Marshal(get(strings.Split(path, ".")...))
+		wantJSON, _ := json.Marshal(want)
+		if string(got) != string(wantJSON) {
+			t.Errorf("%s = %s, want %s", path, got, wantJSON)
+		}
+	}
+
+	if _, err = strongParser.GenerateJSONSchema(42); err == nil {
+		t.Error("GenerateJSONSchema accepted a non-struct value")
+	}
+}
@@ -0,0 +1,138 @@
+package tests
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ALiwoto/ssg/ssg/strongParser"
+)
+
+var errNoAdmins = errors.New("at least one admin is required")
+
+type validatedServer struct {
+	Host     string        `required:"true"`
+	Port     int           `range:"1-65535"`
+	LogLevel string        `oneof:"debug info warn error" default:"info"`
+	Name     string        `regex:"^[a-z][a-z0-9_]*$"`
+	Workers  int           `min:"1" max:"64"`
+	Timeout  time.Duration `max:"1m"`
+	Tags     []string      `nonempty:"true"`
+	ApiUrl   string        `url:"true"`
+	DataDir  string        `path_exists:"true"`
+	Admins   []int64
+	Database *validatedDatabase `required:"true"`
+}
+
+type validatedDatabase struct {
+	Url string `required:"true"`
+}
+
+func (s *validatedServer) Validate() error {
+	if len(s.Admins) == 0 {
+		return errNoAdmins
+	}
+
+	return nil
+}
+
+func TestValidationCollectsAllViolations(t *testing.T) {
+	const config = `
+[main]
+port = 70000
+log_level = verbose
+name = 1bad
+workers = 0
+timeout = 2m
+api_url = not a url
+data_dir = /this/path/does/not/exist
+`
+	value := &validatedServer{}
+	err := strongParser.ParseStringConfigWithOption(value, config, noEnvOptions())
+
+	var validationErrs strongParser.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+
+	expected := map[string]string{
+		"host":      "is required",
+		"port":      "must be in range 1-65535",
+		"log_level": "must be one of",
+		"name":      "must match",
+		"workers":   "must be at least 1",
+		"timeout":   "must be at most 1m",
+		"tags":      "must not be empty",
+		"api_url":   "must be a valid URL",
+		"data_dir":  "does not exist",
+		"":          "section is required",
+	}
+	for _, current := range validationErrs {
+		if current.Err != nil {
+			// reported by the Validate hook.
+			continue
+		}
+
+		reason, ok := expected[current.Key]
+		if !ok {
+			t.Errorf("unexpected violation: %v", current)
+			continue
+		}
+		if !strings.Contains(current.Reason, reason) {
+			t.Errorf("violation of %q is %q, want %q", current.Key, current.Reason, reason)
+		}
+		delete(expected, current.Key)
+	}
+	for key, reason := range expected {
+		t.Errorf("missing violation for key %q: %s", key, reason)
+	}
+
+	// the Validate hook runs too, and its error can be matched.
+	if !errors.Is(err, errNoAdmins) {
+		t.Errorf("the error of the Validate hook was not reported: %v", err)
+	}
+	if !strings.Contains(err.Error(), "section 'main', key 'port'") {
+		t.Errorf("error message doesn't list the section and key:\n%v", err)
+	}
+}
+
+func TestValidationPasses(t *testing.T) {
+	config := `
+[main]
+host = localhost
+port = 8080
+name = my_bot
+workers = 4
+timeout = 30s
+tags = a, b
+api_url = https://api.example.com/v1
+data_dir = ` + t.TempDir() + `
+admins = 1, 2
+
+[database]
+url = postgres://localhost/db
+`
+	value := &validatedServer{}
+	err := strongParser.ParseStringConfigWithOption(value, config, noEnvOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value.LogLevel != "info" {
+		t.Errorf("LogLevel is %q, want the default value", value.LogLevel)
+	}
+}
+
+func TestOptionalSectionIsNotValidated(t *testing.T) {
+	type config struct {
+		Token    string
+		Database *validatedDatabase
+	}
+
+	value := &config{}
+	err := strongParser.ParseStringConfigWithOption(value, "[main]\ntoken = abc\n", noEnvOptions())
+	if err != nil {
+		t.Fatalf("fields of a missing optional section were validated: %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ALiwoto/ssg/ssg/strongParser"
+)
+
+const valueSyntaxConfig = `
+[main]
+; a full-line comment
+welcome = Hello and welcome
+    to our group!
+long_line = first part \
+    second part
+private_key = """
+-----BEGIN KEY-----
+abc
+-----END KEY-----"""
+padded = "  spaced  "   ; keeps its spaces
+escaped = 'tab\there, quote \' and\nnewline'
+hash_value = "#ff8800"
+port = 8080 # the listening port
+url = http://example.com/#anchor
+`
+
+func allValueSyntaxOptions() *strongParser.ConfigParserOptions {
+	return &strongParser.ConfigParserOptions{
+		MainSectionName: strongParser.DefaultMainSection,
+		MultilineValues: true,
+		QuotedValues:    true,
+		InlineComments:  true,
+	}
+}
+
+func TestValueSyntaxOptions(t *testing.T) {
+	p, err := strongParser.ParseStringWithOption(valueSyntaxConfig, allValueSyntaxOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"welcome":     "Hello and welcome\nto our group!",
+		"long_line":   "first part second part",
+		"private_key": "-----BEGIN KEY-----\nabc\n-----END KEY-----",
+		"padded":      "  spaced  ",
+		"escaped":     "tab\there, quote ' and\nnewline",
+		"hash_value":  "#ff8800",
+		"port":        "8080",
+		"url":         "http://example.com/#anchor",
+	}
+	for key, want := range expected {
+		if got, err := p.Get("main", key); err != nil || got != want {
+			t.Errorf("Get(main, %s) = %q, %v; want %q", key, got, err, want)
+		}
+	}
+
+	if p.String() != valueSyntaxConfig {
+		t.Errorf("unchanged config was not written back as is:\n%s", p.String())
+	}
+}
+
+func TestValueSyntaxIsOffByDefault(t *testing.T) {
+	p, err := strongParser.ParseString("[main]\nport = 8080 # the listening port\npadded = \"  a \"\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value, _ := p.Get("main", "port"); value != "8080 # the listening port" {
+		t.Errorf("inline comment was removed without the option: %q", value)
+	}
+	if value, _ := p.Get("main", "padded"); value != `"  a "` {
+		t.Errorf("quotes were removed without the option: %q", value)
+	}
+}
+
+func TestValueSyntaxWriteBack(t *testing.T) {
+	p, err := strongParser.ParseStringWithOption(valueSyntaxConfig, allValueSyntaxOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_ = p.Set("main", "port", "9090")
+	_ = p.Set("main", "welcome", "multi\nline")
+	_ = p.Set("main", "motd", "; not a comment")
+
+	output := p.String()
+	for _, line := range []string{
+		"port = 9090 # the listening port\n",
+		"welcome = \"multi\\nline\"\n",
+		"motd = \"; not a comment\"\n",
+	} {
+		if !strings.Contains(output, line) {
+			t.Errorf("output doesn't contain %q:\n%s", line, output)
+		}
+	}
+
+	reparsed, err := strongParser.ParseStringWithOption(output, allValueSyntaxOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"port", "welcome", "motd", "private_key"} {
+		want, _ := p.Get("main", key)
+		if got, _ := reparsed.Get("main", key); got != want {
+			t.Errorf("%s is %q after writing, want %q", key, got, want)
+		}
+	}
+}
+
+func TestUnterminatedTripleQuote(t *testing.T) {
+	_, err := strongParser.ParseStringWithOption("[main]\nkey = \"\"\"\nabc\n", allValueSyntaxOptions())
+	if err == nil {
+		t.Fatal("an unterminated triple-quoted value was accepted")
+	}
+}
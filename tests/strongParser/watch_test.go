@@ -0,0 +1,152 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ALiwoto/ssg/ssg/strongParser"
+)
+
+type watchedConfig struct {
+	Token    string `required:"true"`
+	Port     int
+	Database struct {
+		Url string
+	}
+}
+
+func TestWatchConfig(t *testing.T) {
+	for _, polling := range []bool{false, true} {
+		dir := writeConfigFiles(t, map[string]string{
+			"config.ini": "[main]\ntoken = first\nport = 80\n\n[database]\nurl = db1\n",
+		})
+		path := filepath.Join(dir, "config.ini")
+
+		changes := make(chan []strongParser.Change, 4)
+		errs := make(chan error, 4)
+		w, err := strongParser.WatchConfig(path, &strongParser.WatchOptions{
+			ParserOptions: noEnvOptions(),
+			Polling:       polling,
+			Interval:      10 * time.Millisecond,
+			Debounce:      20 * time.Millisecond,
+			OnError:       func(err error) { errs <- err },
+		}, func(old, new *watchedConfig, diff []strongParser.Change) {
+			if old.Token != "first" || new.Token != "first" {
+				t.Errorf("onChange got tokens %q and %q", old.Token, new.Token)
+			}
+			changes <- diff
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer w.Close()
+
+		if w.Current().Port != 80 {
+			t.Fatalf("polling %v: port = %d, want 80", polling, w.Current().Port)
+		}
+
+		// make sure the modification time differs for polling.
+		time.Sleep(20 * time.Millisecond)
+		writeFile(t, path, "[main]\ntoken = first\nport = 81\n\n[database]\nurl = db2\n")
+
+		select {
+		case diff := <-changes:
+			if len(diff) != 2 || diff[0].Field != "Port" || diff[0].Old != 80 || diff[0].New != 81 ||
+				diff[1].Field != "Database.Url" || diff[1].New != "db2" {
+				t.Errorf("polling %v: diff = %+v", polling, diff)
+			}
+		case err := <-errs:
+			t.Fatalf("polling %v: %v", polling, err)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("polling %v: the change was not reported", polling)
+		}
+
+		if w.Current().Port != 81 {
+			t.Errorf("polling %v: port = %d after the change, want 81", polling, w.Current().Port)
+		}
+
+		// the token is required, so this edit is rejected.
+		time.Sleep(20 * time.Millisecond)
+		writeFile(t, path, "[main]\nport = 82\n")
+
+		select {
+		case <-errs:
+		case diff := <-changes:
+			t.Fatalf("polling %v: an invalid edit was applied: %+v", polling, diff)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("polling %v: the invalid edit was not reported", polling)
+		}
+
+		if w.Current().Port != 81 {
+			t.Errorf("polling %v: port = %d after an invalid edit, want 81", polling, w.Current().Port)
+		}
+	}
+}
+
+func TestWatchConfigIncludedFiles(t *testing.T) {
+	for _, polling := range []bool{false, true} {
+		dir := writeConfigFiles(t, map[string]string{
+			"config.ini":            "include = shared/base.ini\n\n[main]\ntoken = first\n",
+			"config.production.ini": "[main]\nport = 80\n",
+			"shared/base.ini":       "[database]\nurl = db1\n",
+		})
+
+		opt := includeOptions()
+		opt.Profile = "production"
+
+		changes := make(chan []strongParser.Change, 4)
+		w, err := strongParser.WatchConfig(filepath.Join(dir, "config.ini"), &strongParser.WatchOptions{
+			ParserOptions: opt,
+			Polling:       polling,
+			Interval:      10 * time.Millisecond,
+			Debounce:      20 * time.Millisecond,
+			OnError:       func(err error) { t.Errorf("polling %v: %v", polling, err) },
+		}, func(old, new *watchedConfig, diff []strongParser.Change) {
+			changes <- diff
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer w.Close()
+
+		for _, edit := range []struct{ name, content, field string }{
+			{"shared/base.ini", "[database]\nurl = db2\n", "Database.Url"},
+			{"config.production.ini", "[main]\nport = 81\n", "Port"},
+		} {
+			time.Sleep(20 * time.Millisecond)
+			writeFile(t, filepath.Join(dir, edit.name), edit.content)
+
+			select {
+			case diff := <-changes:
+				if len(diff) != 1 || diff[0].Field != edit.field {
+					t.Errorf("polling %v: diff = %+v after editing %s", polling, diff, edit.name)
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatalf("polling %v: the change of %s was not reported", polling, edit.name)
+			}
+		}
+
+		if w.Current().Database.Url != "db2" || w.Current().Port != 81 {
+			t.Errorf("polling %v: got %+v", polling, *w.Current())
+		}
+	}
+}
+
+func TestWatchConfigInvalidFile(t *testing.T) {
+	_, err := strongParser.WatchConfig[watchedConfig](
+		filepath.Join(t.TempDir(), "missing.ini"), nil, nil,
+	)
+	if err == nil {
+		t.Fatal("WatchConfig succeeded without a config file")
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,206 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ALiwoto/ssg/ssg/strongParser"
+)
+
+const roundTripSource = `# bot configuration
+[main]
+; the token of the bot
+token   =   12345:abcd
+owner_id = 1
+
+# database settings
+[database]
+url = postgres://localhost/db
+max_connections=10
+`
+
+func TestWriteToKeepsOriginalLayout(t *testing.T) {
+	p, err := strongParser.ParseString(roundTripSource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := p.String(); got != roundTripSource {
+		t.Fatalf("unchanged config was not written back as is:\n%s", got)
+	}
+}
+
+func TestWriteToAppliesChanges(t *testing.T) {
+	p, err := strongParser.ParseString(roundTripSource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_ = p.Set("main", "token", "67890:efgh")
+	_ = p.Set("main", "log_channel", "-100123")
+	_ = p.RemoveOption("database", "url")
+	if err = p.AddSection("redis"); err != nil {
+		t.Fatal(err)
+	}
+	_ = p.Set("redis", "address", "localhost:6379")
+
+	const expected = `# bot configuration
+[main]
+; the token of the bot
+token   =   67890:efgh
+owner_id = 1
+log_channel = -100123
+
+# database settings
+[database]
+max_connections=10
+
+[redis]
+address = localhost:6379
+`
+	if got := p.String(); got != expected {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", got, expected)
+	}
+}
+
+func TestSaveFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config.ini")
+	if err := os.WriteFile(filename, []byte(roundTripSource), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := strongParser.Parse(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_ = p.Set("main", "owner_id", "2")
+	if err = p.SaveFile(filename); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("SaveFile changed the file mode to %v", info.Mode().Perm())
+	}
+
+	reloaded, err := strongParser.Parse(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, _ := reloaded.Get("main", "owner_id"); value != "2" {
+		t.Errorf("owner_id is %q after SaveFile, want 2", value)
+	}
+	if value, _ := reloaded.Get("main", "token"); value != "12345:abcd" {
+		t.Errorf("token is %q after SaveFile, want the original value", value)
+	}
+}
+
+type marshalConfig struct {
+	Token    string
+	Prefix   rune `key:"cmd_prefix" type:"rune"`
+	OwnerIds []int64
+	Debug    bool
+	Ratio    float64
+	Database dbConfig
+	Redis    *redisConfig `section:"cache"`
+	Missing  *redisConfig
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	original := &marshalConfig{
+		Token:    "12345:abcd",
+		Prefix:   '/',
+		OwnerIds: []int64{1, 2, 3},
+		Debug:    true,
+		Ratio:    0.5,
+		Database: dbConfig{
+			Url:     "postgres://localhost/db",
+			MaxConn: 10,
+			Replica: replicaConfig{Host: "replica.local", Port: 5433},
+		},
+		Redis: &redisConfig{Address: "localhost:6379"},
+	}
+
+	data, err := strongParser.Marshal(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed := &marshalConfig{}
+	if err = strongParser.ParseStringConfig(parsed, string(data)); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(original, parsed) {
+		t.Fatalf("Marshal output didn't round-trip:\n%s\ngot %+v", data, parsed)
+	}
+}
+
+type quotedSliceConfig struct {
+	Names  []string
+	Plain  []string
+	Quoted []string
+}
+
+func TestMarshalSliceRoundTrip(t *testing.T) {
+	original := &quotedSliceConfig{
+		Names:  []string{"a b", "c"},
+		Plain:  []string{"x", "y"},
+		Quoted: []string{`say "hi"`, "it's", "1, 2", ""},
+	}
+
+	data, err := strongParser.Marshal(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, opt := range []*strongParser.ConfigParserOptions{
+		noEnvOptions(),
+		{MainSectionName: strongParser.DefaultMainSection, QuotedValues: true},
+	} {
+		parsed := &quotedSliceConfig{}
+		err = strongParser.ParseStringConfigWithOption(parsed, string(data), opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(original, parsed) {
+			t.Errorf("slices didn't round-trip (QuotedValues: %v):\n%s\ngot %q",
+				opt.QuotedValues, data, *parsed)
+		}
+	}
+}
+
+func TestSliceQuotesWithoutQuotedValues(t *testing.T) {
+	const value = "[main]\nnames = O'Brien, x\nplain = \"a b\", c\n"
+
+	config := &quotedSliceConfig{}
+	err := strongParser.ParseStringConfigWithOption(config, value, noEnvOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// quotes are a part of the elements, unless QuotedValues is set.
+	if !reflect.DeepEqual(config.Names, []string{"O'Brien", "x"}) ||
+		!reflect.DeepEqual(config.Plain, []string{`"a`, `b"`, "c"}) {
+		t.Errorf("got %q and %q", config.Names, config.Plain)
+	}
+
+	opt := noEnvOptions()
+	opt.QuotedValues = true
+	config = &quotedSliceConfig{}
+	err = strongParser.ParseStringConfigWithOption(config, "[main]\nplain = x, \"a b\"\n", opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(config.Plain, []string{"x", "a b"}) {
+		t.Errorf("got %q with QuotedValues", config.Plain)
+	}
+}